@@ -2,12 +2,13 @@ package boilerpipe
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"strings"
 	"time"
 
+	"github.com/jlubawy/go-boilerpipe/normurl"
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 )
@@ -17,65 +18,217 @@ const Version = "0.5.0"
 
 type Document struct {
 	// Title is the title of the document.
-	Title string
+	Title string `json:"title"`
 
-	Author string
+	// CleanTitle is the title of the document without its site name (e.g.
+	// "Headline" for "Headline | Site Name").
+	CleanTitle string `json:"cleanTitle"`
+
+	// Description is the description of the document from its <meta> tags,
+	// or else from its schema.org article.
+	Description string `json:"description"`
+
+	// Author is the name of the most likely author of the document.
+	Author string `json:"author"`
+
+	// Authors are all of the authors found in the document, ordered from the
+	// most to the least likely.
+	Authors []Author `json:"authors"`
 
 	// Date is the date the document was created.
-	Date time.Time
+	Date time.Time `json:"date"`
+
+	// DateModified is the date the document was last modified.
+	DateModified time.Time `json:"dateModified"`
+
+	// DateCandidates are all of the dates found in the document, which Date
+	// and DateModified are resolved from.
+	DateCandidates []DateCandidate `json:"dateCandidates"`
+
+	// Language is the lowercase ISO 639-1 code of the document's language
+	// (e.g. "en"), or an empty string if unknown. The declared language is
+	// used unless the text is written in a different script.
+	Language string `json:"language"`
+
+	// URL is the URL of the document, if known. See SetURL.
+	URL *normurl.URL `json:"url"`
+
+	// CanonicalURL is the <link rel="canonical"> URL of the document, or else
+	// its og:url, resolved against the base URL. It is nil if there is none,
+	// or if it is relative and the document URL is unknown.
+	CanonicalURL *normurl.URL `json:"canonicalUrl"`
+
+	// BaseURL is the <base href> URL of the document, resolved against the
+	// document URL, or nil if there is none.
+	BaseURL *normurl.URL `json:"baseUrl"`
+
+	// NextPageURL is the URL of the next page of the document, from its
+	// rel="next" links or else the "next" link of its pagination, or nil if
+	// there is none. See FetchPages.
+	NextPageURL *normurl.URL `json:"nextPageUrl"`
+
+	// PageURLs are the URLs of the numbered page links of the document's
+	// pagination, by page number.
+	PageURLs []*normurl.URL `json:"pageUrls"`
+
+	// SiteName is the og:site_name of the document, or else its
+	// application-name, or else the name of its publisher.
+	SiteName string `json:"siteName"`
+
+	// Publisher is the schema.org publisher of the document's article, or nil
+	// if there is none.
+	Publisher *SchemaEntity `json:"publisher"`
+
+	// Metadata is the metadata found in the document's <meta> tags.
+	Metadata Metadata `json:"metadata"`
+
+	// LinkedData is the schema.org Article found in the document's JSON-LD
+	// scripts, or nil if there is none.
+	LinkedData *SchemaArticle `json:"linkedData"`
+
+	// Microdata is the schema.org Article found in the document's microdata
+	// and RDFa properties, or nil if there is none.
+	Microdata *SchemaArticle `json:"microdata"`
+
+	// Comments are the reader comments found in the comment section of the
+	// document, in document order.
+	Comments []Comment `json:"comments"`
+
+	// Tables are the data tables of the document, in document order. Layout
+	// tables are not included. See ContentTables.
+	Tables []*Table `json:"tables"`
+
+	TextBlocks []*TextBlock `json:"textBlocks"`
 
-	TextBlocks []*TextBlock
+	// Trace records why the filters of a Pipeline changed the text blocks,
+	// if it's not nil. See Trace.
+	Trace *Trace `json:"-"`
 
-	linkedDataArticle linkedDataArticle
+	baseHref      string
+	canonicalRefs []string
+	nextPageRefs  []string
+	pageRefs      map[int]string
 }
 
 // ParseDocument parses an HTML document and returns a Document for further
 // processing through filters.
+//
+// If the document can't be read to its end, the Document of the part which
+// was parsed is returned along with a *ParseError.
 func ParseDocument(r io.Reader) (*Document, error) {
-	var h *contentHandler
-	h, err := parse(r, func(tok *html.Token, h *contentHandler) {
+	return ParseDocumentContext(context.Background(), r, nil)
+}
+
+// ParseDocumentContext is like ParseDocumentOptions, but stops parsing the
+// document when the context is done. The Document of the part which was
+// parsed is then returned along with a *ParseError whose kind is ErrCanceled.
+func ParseDocumentContext(ctx context.Context, r io.Reader, opts *ParseOptions) (*Document, error) {
+	h, err := parse(ctx, r, opts, func(tok *html.Token, h *contentHandler) {
 		h.TextToken(tok)
 	})
-	if err != nil {
-		return nil, err
-	}
+	return newDocument(h), err
+}
 
+// newDocument returns the Document of a content handler which has been given
+// the whole document.
+func newDocument(h *contentHandler) *Document {
 	h.FlushBlock()
 
 	doc := &Document{}
 
-	// Parse linked-data JSON
-	for _, s := range h.linkedDataJSON {
-		if err := json.Unmarshal([]byte(s), &doc.linkedDataArticle); err != nil {
-			continue // try the next if multiple
-		}
-		if doc.linkedDataArticle.Type == "Article" {
-			break
-		}
+	doc.Metadata = *newMetadata(h.metadata)
+	doc.LinkedData = parseLinkedData(h.linkedDataJSON)
+	doc.Microdata = h.microdata.article()
+
+	ld := doc.LinkedData
+	if ld == nil {
+		ld = &SchemaArticle{}
+	}
+	md := doc.Microdata
+	if md == nil {
+		md = &SchemaArticle{}
 	}
 
-	if doc.linkedDataArticle.Headline != "" {
-		doc.Title = doc.linkedDataArticle.Headline
-	} else {
+	if ld.Headline != "" {
+		doc.Title = ld.Headline
+	} else if h.title != "" {
 		doc.Title = h.title
+	} else if doc.Metadata.Title != "" {
+		doc.Title = doc.Metadata.Title
+	} else {
+		doc.Title = md.Headline
+	}
+
+	doc.TextBlocks = h.textBlocks
+
+	doc.Comments = h.comments.documentComments()
+	doc.Tables = h.tables.tables
+
+	doc.Description = doc.Metadata.Description
+	if doc.Description == "" {
+		doc.Description = ld.Description
+	}
+	if doc.Description == "" {
+		doc.Description = md.Description
+	}
+
+	doc.Authors = authorCandidates(map[AuthorSource][]string{
+		AuthorSourceLinkedData: ld.AuthorNames(),
+		AuthorSourceMicrodata:  md.AuthorNames(),
+		AuthorSourceRelAuthor:  h.authorLinks,
+		AuthorSourceMeta:       doc.Metadata.Authors,
+		AuthorSourceByline:     bylineBlocks(doc.TextBlocks),
+	})
+	if len(doc.Authors) > 0 {
+		doc.Author = doc.Authors[0].Name
 	}
 
-	doc.Author = doc.linkedDataArticle.Author.Name
+	declared := h.lang
+	for _, tag := range []string{ld.Language, md.Language, doc.Metadata.Language} {
+		if declared != "" {
+			break
+		}
+		declared = normalizeLanguage(tag)
+	}
+	doc.Language = resolveLanguage(declared, detectDocumentLanguage(doc.TextBlocks))
 
-	if !doc.linkedDataArticle.DatePublished.IsZero() {
-		doc.Date = doc.linkedDataArticle.DatePublished
+	if ld.Publisher != nil {
+		doc.Publisher = ld.Publisher
 	} else {
-		doc.Date = h.time
+		doc.Publisher = md.Publisher
 	}
 
-	doc.TextBlocks = h.textBlocks
+	doc.SiteName = doc.Metadata.SiteName
+	if doc.SiteName == "" {
+		doc.SiteName = doc.Metadata.first("application-name")
+	}
+	if doc.SiteName == "" && doc.Publisher != nil {
+		doc.SiteName = doc.Publisher.Name
+	}
 
-	return doc, nil
+	doc.baseHref = h.baseHref
+	doc.canonicalRefs = append(doc.canonicalRefs, h.links["canonical"]...)
+	if doc.Metadata.URL != "" {
+		doc.canonicalRefs = append(doc.canonicalRefs, doc.Metadata.URL)
+	}
+	doc.nextPageRefs = h.pagination.nextPageRefs(h.links["next"])
+	doc.pageRefs = h.pagination.numberedPageRefs()
+	doc.resolveURLs()
+	doc.CleanTitle = doc.cleanTitle()
+
+	doc.DateCandidates = append(doc.DateCandidates, schemaDateCandidates(doc.LinkedData, DateSourceLinkedData)...)
+	doc.DateCandidates = append(doc.DateCandidates, schemaDateCandidates(doc.Microdata, DateSourceMicrodata)...)
+	doc.DateCandidates = append(doc.DateCandidates, metaDateCandidates(&doc.Metadata)...)
+	doc.DateCandidates = append(doc.DateCandidates, h.dates...)
+	doc.DateCandidates = append(doc.DateCandidates, textDateCandidates(doc.TextBlocks)...)
+	doc.resolveDates()
+
+	return doc
 }
 
 func (doc *Document) Content() string {
-	if doc.linkedDataArticle.Body != "" {
-		return doc.linkedDataArticle.Body
+	if doc.LinkedData != nil && doc.LinkedData.Body != "" {
+		return doc.LinkedData.Body
 	}
 	return doc.Text(true, false)
 }
@@ -105,67 +258,135 @@ func (doc *Document) Text(includeContent, includeNonContent bool) string {
 	return html.EscapeString(strings.Trim(buf.String(), " \n"))
 }
 
-func parse(r io.Reader, fn func(tok *html.Token, h *contentHandler)) (h *contentHandler, err error) {
+func parse(ctx context.Context, r io.Reader, opts *ParseOptions, fn func(tok *html.Token, h *contentHandler)) (h *contentHandler, err error) {
+	if opts == nil {
+		opts = &defaultParseOptions
+	}
+
 	h = newContentHandler()
 
-	z := html.NewTokenizer(r)
-	for {
+	if opts.MaxBytes > 0 {
+		r = &limitReader{r: r, n: opts.MaxBytes}
+	}
+	cr := &countingReader{r: r}
+	z := html.NewTokenizer(cr)
+	done := ctx.Done()
+	for numTokens := 1; ; numTokens++ {
+		select {
+		case <-done:
+			h.closeElements(0)
+			return h, newParseError(ctx.Err(), cr.n)
+		default:
+		}
+
 		tt := z.Next()
 		tok := z.Token()
 
+		// Stop before handling the token which exceeds the limit
+		if opts.MaxTokens > 0 && numTokens > opts.MaxTokens && tt != html.ErrorToken {
+			h.closeElements(0)
+			opts.truncate(h)
+			return h, newParseError(ErrMaxTokens, cr.n)
+		}
+
 		switch tt {
 		case html.ErrorToken:
 			if z.Err() != io.EOF {
-				err = z.Err()
+				err = newParseError(z.Err(), cr.n)
 			}
-			goto DONE
+
+			// End the elements left open at the end of the document
+			h.closeElements(0)
+			return
 
 		case html.TextToken:
-			if h.inLinkedDataJSON {
-				h.linkedDataJSON = append(h.linkedDataJSON, tok.Data)
-			}
-			fn(&tok, h)
+			h.beforeText(&tok)
+			h.handleText(&tok, fn)
 
 		case html.StartTagToken:
-			// If the token is start tag, but should be a self-closing tag,
-			// then the token is malformed and should be skipped.
-			if shouldBeSelfClosingTag(tok.DataAtom) {
-				continue
+			if h.beforeStartTag(&tok) {
+				h.handleStartTag(&tok)
 			}
 
-			if tok.DataAtom == atom.Script {
-				for _, attr := range tok.Attr {
-					if attr.Key == "type" && attr.Val == "application/ld+json" {
-						h.inLinkedDataJSON = true
-					}
-				}
+		case html.EndTagToken:
+			// The body is ended at the end of the document since browsers
+			// add any content after it to the body
+			if tok.DataAtom != atom.Body && tok.DataAtom != atom.Html {
+				h.handleEndTag(&tok)
 			}
-			h.StartElement(&tok)
 
-		case html.EndTagToken:
-			if h.inLinkedDataJSON {
-				h.inLinkedDataJSON = false
+		case html.SelfClosingTagToken:
+			// Only void elements and elements of foreign content, such as
+			// SVG, are ended by the self-closing flag, other elements are
+			// left open
+			if h.beforeStartTag(&tok) {
+				if h.atomStack.isOpen(atom.Svg, atom.Math) {
+					h.handleSelfClosingTag(&tok)
+				} else {
+					h.handleStartTag(&tok)
+				}
 			}
-			h.EndElement(&tok)
 
-		case html.SelfClosingTagToken, html.CommentToken, html.DoctypeToken:
+		case html.CommentToken, html.DoctypeToken:
 			// do nothing
 		}
+
+		if limitErr := opts.exceededLimit(h); limitErr != nil {
+			h.closeElements(0)
+			opts.truncate(h)
+			return h, newParseError(limitErr, cr.n)
+		}
+	}
+}
+
+// handleText handles a text token, passing it to fn.
+func (h *contentHandler) handleText(tok *html.Token, fn func(tok *html.Token, h *contentHandler)) {
+	if h.inLinkedDataJSON {
+		h.linkedDataJSON = append(h.linkedDataJSON, tok.Data)
+	}
+	fn(tok, h)
+}
+
+// handleStartTag handles a start tag token.
+func (h *contentHandler) handleStartTag(tok *html.Token) {
+	// Void elements have no end tag
+	if isVoidElement(tok.DataAtom) {
+		h.handleSelfClosingTag(tok)
+		return
 	}
 
-DONE:
-	return
+	if tok.DataAtom == atom.Script {
+		for _, attr := range tok.Attr {
+			if attr.Key == "type" && attr.Val == "application/ld+json" {
+				h.inLinkedDataJSON = true
+			}
+		}
+	}
+	h.StartElement(tok)
 }
 
-type linkedDataArticle struct {
-	Type          string           `json:"@type"`
-	Headline      string           `json:"headline"`
-	DatePublished time.Time        `json:"datePublished"`
-	Author        linkedDataAuthor `json:"author"`
-	Body          string           `json:"articleBody"`
+// handleEndTag handles an end tag token, ending the open elements within the
+// element it closes. End tags which don't match an open element are ignored.
+func (h *contentHandler) handleEndTag(tok *html.Token) {
+	if i := h.atomStack.endTagIndex(tok); i != -1 {
+		h.closeElements(i)
+	}
 }
 
-type linkedDataAuthor struct {
-	Type string `json:"@type"`
-	Name string `json:"name"`
+// handleSelfClosingTag handles the start tag of a void element, or a
+// self-closing tag within foreign content, which are started and ended at
+// once.
+func (h *contentHandler) handleSelfClosingTag(tok *html.Token) {
+	switch tok.DataAtom {
+	case atom.Meta:
+		h.MetaElement(tok)
+	case atom.Link, atom.Base:
+		h.LinkElement(tok)
+	}
+	h.StartElement(tok)
+	h.EndElement(&html.Token{
+		Type:     html.EndTagToken,
+		DataAtom: tok.DataAtom,
+		Data:     tok.Data,
+	})
 }
@@ -0,0 +1,270 @@
+package boilerpipe
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// AuthorSource is where an author candidate was found.
+type AuthorSource int
+
+const (
+	AuthorSourceLinkedData AuthorSource = iota
+	AuthorSourceMicrodata
+	AuthorSourceRelAuthor
+	AuthorSourceMeta
+	AuthorSourceByline
+)
+
+var authorSourceNames = [...]string{
+	AuthorSourceLinkedData: "linked-data",
+	AuthorSourceMicrodata:  "microdata",
+	AuthorSourceRelAuthor:  "rel-author",
+	AuthorSourceMeta:       "meta",
+	AuthorSourceByline:     "byline",
+}
+
+func (s AuthorSource) String() string {
+	if s < 0 || int(s) >= len(authorSourceNames) {
+		return "AuthorSource(" + strconv.Itoa(int(s)) + ")"
+	}
+	return authorSourceNames[s]
+}
+
+// MarshalText encodes the source as its name.
+func (s AuthorSource) MarshalText() ([]byte, error) {
+	if s < 0 || int(s) >= len(authorSourceNames) {
+		return nil, fmt.Errorf("boilerpipe: invalid author source %d", int(s))
+	}
+	return []byte(authorSourceNames[s]), nil
+}
+
+// UnmarshalText decodes a source from its name.
+func (s *AuthorSource) UnmarshalText(text []byte) error {
+	for i, name := range authorSourceNames {
+		if name == string(text) {
+			*s = AuthorSource(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("boilerpipe: unknown author source %q", text)
+}
+
+// authorSourceConfidence is the confidence of an author found in each
+// source.
+var authorSourceConfidence = [...]float64{
+	AuthorSourceLinkedData: 0.9,
+	AuthorSourceMicrodata:  0.8,
+	AuthorSourceRelAuthor:  0.7,
+	AuthorSourceMeta:       0.6,
+	AuthorSourceByline:     0.5,
+}
+
+// An Author is a document author along with where it was found and how
+// confident the extraction is, from 0 to 1.
+type Author struct {
+	Name       string         `json:"name"`
+	Sources    []AuthorSource `json:"sources"`
+	Confidence float64        `json:"confidence"`
+}
+
+// AuthorLinkStart starts collecting the text of a rel="author" link.
+func (h *contentHandler) AuthorLinkStart(tok *html.Token, depth int) {
+	if tok.DataAtom != atom.A || h.authorLinkDepth != 0 {
+		return
+	}
+	for _, attr := range tok.Attr {
+		if attr.Key != "rel" {
+			continue
+		}
+		for _, rel := range strings.Fields(strings.ToLower(attr.Val)) {
+			if rel == "author" {
+				h.authorLinkDepth = depth
+				h.authorLinkText.Reset()
+				return
+			}
+		}
+	}
+}
+
+// AuthorLinkText adds text to the rel="author" link being collected.
+func (h *contentHandler) AuthorLinkText(text string) {
+	if h.authorLinkDepth != 0 {
+		h.authorLinkText.WriteString(text)
+	}
+}
+
+// AuthorLinkEnd completes the rel="author" link at the given depth.
+func (h *contentHandler) AuthorLinkEnd(depth int) {
+	if h.authorLinkDepth == 0 || depth > h.authorLinkDepth {
+		return
+	}
+	if name := cleanAuthorName(h.authorLinkText.String()); name != "" {
+		h.authorLinks = append(h.authorLinks, name)
+	}
+	h.authorLinkDepth = 0
+}
+
+// maxBylineWords is the maximum number of words a byline text block can have.
+const maxBylineWords = 15
+
+var (
+	reBylinePrefix        = regexp.MustCompile(`(?i)^(?:written\s+by|posted\s+by|reported\s+by|story\s+by|words\s+by|by|author|authors|von|par|por|di)\s*:?\s+(.+)$`)
+	reBylinePrefixCJK     = regexp.MustCompile(`^[（(]?(?:文|图文|撰文|记者|作者|本报记者|特约记者|通讯员)(?:\s*[/／｜|:：]\s*(?:记者|本报记者|特约记者)?|\s+)\s*([^）)]+)[）)]?$`)
+	reAuthorNameSeparator = regexp.MustCompile(`(?i)\s*(?:,|;|&|\band\b|\bund\b|\bet\b|、|，|；|\s[-–—]\s)\s*`)
+	reCJKNameSeparator    = regexp.MustCompile(`[\s、，,／/]+`)
+)
+
+// bylineAuthors returns the authors of a byline-shaped text such as
+// "By Jane Doe and John Doe" or "文/记者 张三", or nil if the text is not a
+// byline.
+func bylineAuthors(text string) (names []string) {
+	text = strings.Join(strings.Fields(text), " ")
+
+	if ss := reBylinePrefixCJK.FindStringSubmatch(text); ss != nil {
+		for _, name := range reCJKNameSeparator.Split(ss[1], -1) {
+			if name = cleanAuthorName(name); name != "" {
+				names = appendUnique(names, name)
+			}
+		}
+		return
+	}
+
+	ss := reBylinePrefix.FindStringSubmatch(text)
+	if ss == nil {
+		return
+	}
+
+	// Stop at the first parenthetical or separator
+	rest := ss[1]
+	if i := strings.IndexAny(rest, "|(•·\n"); i != -1 {
+		rest = rest[:i]
+	}
+
+	// Names end at the first part that doesn't look like one, such as a
+	// date or a job title.
+	for _, name := range reAuthorNameSeparator.Split(rest, -1) {
+		if name = cleanAuthorName(name); name == "" || !isAuthorName(name) {
+			break
+		}
+		names = appendUnique(names, name)
+	}
+	return
+}
+
+// isAuthorName returns true if s looks like a person or organization name,
+// with between one and five words that all start with an uppercase letter or
+// are common lowercase name particles.
+func isAuthorName(s string) bool {
+	words := strings.Fields(s)
+	if len(words) == 0 || len(words) > 5 {
+		return false
+	}
+	for _, w := range words {
+		r := []rune(w)[0]
+		switch {
+		case unicode.IsUpper(r):
+		case unicode.Is(unicode.Han, r):
+		case w == "de" || w == "van" || w == "von" || w == "der" || w == "da" || w == "la" || w == "le" || w == "bin" || w == "al":
+		default:
+			return false
+		}
+		if strings.IndexFunc(w, unicode.IsDigit) != -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// genericAuthorNames are placeholder author names used by some CMSs.
+var genericAuthorNames = map[string]bool{
+	"admin":         true,
+	"administrator": true,
+	"author":        true,
+	"editor":        true,
+	"staff":         true,
+	"unknown":       true,
+	"writer":        true,
+}
+
+// isGenericAuthorName returns true if a name is a placeholder or contains no
+// letters.
+func isGenericAuthorName(s string) bool {
+	return genericAuthorNames[strings.ToLower(s)] || strings.IndexFunc(s, unicode.IsLetter) == -1
+}
+
+// cleanAuthorName removes surrounding whitespace and punctuation from a name.
+// Byline prefixes such as "By" are only removed from visible bylines by
+// bylineAuthors, since they're also the start of names such as "Di Wu".
+func cleanAuthorName(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) && r != '.'
+	})
+	s = strings.TrimRight(s, ". ")
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "/") {
+		return ""
+	}
+	return s
+}
+
+// bylineBlocks labels the byline-shaped text blocks and returns their
+// authors.
+func bylineBlocks(textBlocks []*TextBlock) (names []string) {
+	for _, tb := range textBlocks {
+		if tb.NumWords > maxBylineWords || tb.HasLabel(LabelComment) {
+			continue
+		}
+		authors := bylineAuthors(tb.Text)
+		if len(authors) == 0 {
+			continue
+		}
+		tb.AddLabels(LabelByline)
+		for _, name := range authors {
+			names = appendUnique(names, name)
+		}
+	}
+	return
+}
+
+// authorCandidates merges the authors found in each source, and returns them
+// ordered by confidence. An author found in several sources gains confidence
+// for each additional source.
+func authorCandidates(sources map[AuthorSource][]string) (authors []Author) {
+	index := make(map[string]int)
+
+	for source := AuthorSourceLinkedData; source <= AuthorSourceByline; source++ {
+		for _, name := range sources[source] {
+			if name = cleanAuthorName(name); name == "" || isGenericAuthorName(name) {
+				continue
+			}
+
+			key := strings.ToLower(name)
+			if i, exists := index[key]; exists {
+				a := &authors[i]
+				a.Sources = append(a.Sources, source)
+				a.Confidence += (1 - a.Confidence) * authorSourceConfidence[source] / 2
+				continue
+			}
+
+			index[key] = len(authors)
+			authors = append(authors, Author{
+				Name:       name,
+				Sources:    []AuthorSource{source},
+				Confidence: authorSourceConfidence[source],
+			})
+		}
+	}
+
+	sort.SliceStable(authors, func(i, j int) bool {
+		return authors[i].Confidence > authors[j].Confidence
+	})
+	return
+}
@@ -0,0 +1,122 @@
+package boilerpipe
+
+import (
+	"strings"
+	"testing"
+)
+
+var bylineAuthorsTestData = map[string][]string{
+	"By Jane Doe":                             {"Jane Doe"},
+	"By JANE DOE and John Smith":              {"JANE DOE", "John Smith"},
+	"Written by Jane Doe, John Smith & Li Na": {"Jane Doe", "John Smith", "Li Na"},
+	"By Sun Staff (contact)":                  {"Sun Staff"},
+	"By Jane Doe, May 20, 2017":               {"Jane Doe"},
+	"By Jane Doe | Senior Writer":             {"Jane Doe"},
+	"Author: Ludwig van Beethoven":            {"Ludwig van Beethoven"},
+	"By Di Wu":                                {"Di Wu"},
+	"文/记者 张三":                                 {"张三"},
+	"（记者 李四 王五）":                              {"李四", "王五"},
+	"By the way, this is not a byline":        nil,
+	"Bye for now":                             nil,
+	"Hello world":                             nil,
+}
+
+func TestBylineAuthors(t *testing.T) {
+	for text, exp := range bylineAuthorsTestData {
+		act := bylineAuthors(text)
+		if len(act) != len(exp) {
+			t.Errorf("%s: expected %v but got %v", text, exp, act)
+			continue
+		}
+		for i := range act {
+			if act[i] != exp[i] {
+				t.Errorf("%s: expected %v but got %v", text, exp, act)
+				break
+			}
+		}
+	}
+}
+
+const authorsTestHTML = `<html>
+<head>
+<meta name="author" content="Jane Doe">
+<meta property="article:author" content="https://example.com/staff/jane-doe/">
+<meta name="author" content="writer">
+</head>
+<body>
+<p>By <a rel="author" href="/staff/jane-doe/">Jane Doe</a></p>
+<p>Photo by John Smith</p>
+<p>By John Smith</p>
+</body>
+</html>`
+
+func TestAuthors(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(authorsTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := len(doc.Authors); l != 2 {
+		t.Fatalf("expected 2 authors but got %d: %v", l, doc.Authors)
+	}
+
+	jane := doc.Authors[0]
+	if jane.Name != "Jane Doe" || doc.Author != jane.Name {
+		t.Errorf("expected author 'Jane Doe' but got '%s'", jane.Name)
+	}
+	if l := len(jane.Sources); l != 3 {
+		t.Errorf("expected 3 sources but got %v", jane.Sources)
+	}
+
+	john := doc.Authors[1]
+	if john.Name != "John Smith" || john.Sources[0] != AuthorSourceByline {
+		t.Errorf("unexpected author %+v", john)
+	}
+	if john.Confidence >= jane.Confidence {
+		t.Errorf("expected '%s' to be less confident than '%s'", john.Name, jane.Name)
+	}
+
+	numBylines := 0
+	for _, tb := range doc.TextBlocks {
+		if tb.HasLabel(LabelByline) {
+			numBylines++
+			tb.IsContent = true
+		}
+	}
+	if numBylines != 2 {
+		t.Errorf("expected 2 byline blocks but got %d", numBylines)
+	}
+
+	BylineToBoilerplate().Process(doc)
+	for _, tb := range doc.TextBlocks {
+		if tb.HasLabel(LabelByline) && tb.IsContent {
+			t.Errorf("expected byline block '%s' to not be content", tb.Text)
+		}
+	}
+}
+
+func TestAuthorsStructuredNames(t *testing.T) {
+	const s = `<html>
+<head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "NewsArticle", "headline": "The headline", "author": {"@type": "Person", "name": "Di Wu"}}</script>
+<meta name="author" content="Von Trier">
+</head>
+<body><p>The text of the article.</p></body>
+</html>`
+
+	doc, err := ParseDocument(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := "Di Wu"; doc.Author != exp {
+		t.Errorf("expected author '%s' but got '%s'", exp, doc.Author)
+	}
+	var names []string
+	for _, a := range doc.Authors {
+		names = append(names, a.Name)
+	}
+	if exp := "Di Wu,Von Trier"; strings.Join(names, ",") != exp {
+		t.Errorf("expected authors '%s' but got '%s'", exp, strings.Join(names, ","))
+	}
+}
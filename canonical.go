@@ -0,0 +1,109 @@
+package boilerpipe
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// LinkElement collects the href of a <link> tag for each of its rel values,
+// and the href of the first <base> tag.
+func (h *contentHandler) LinkElement(tok *html.Token) {
+	var rels []string
+	var href string
+	hasHref := false
+
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "rel":
+			rels = strings.Fields(strings.ToLower(attr.Val))
+		case "href":
+			href = strings.TrimSpace(attr.Val)
+			hasHref = true
+		}
+	}
+
+	if !hasHref {
+		return
+	}
+
+	if tok.DataAtom == atom.Base {
+		if h.baseHref == "" {
+			h.baseHref = href
+		}
+		return
+	}
+
+	for _, rel := range rels {
+		h.links[rel] = append(h.links[rel], href)
+	}
+}
+
+// resolveURL resolves a possibly relative reference against the base URL,
+// and returns nil if the reference is not an HTTP URL or is relative without a
+// base URL. The result is not normalized, since normalizing a base URL would
+// remove the trailing slash its relative references depend on.
+func resolveURL(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil
+	}
+
+	if u.Host == "" {
+		if base == nil || u.Scheme != "" {
+			return nil
+		}
+		u = base.ResolveReference(u)
+	} else if u.Scheme == "" {
+		u.Scheme = "http"
+		if base != nil {
+			u.Scheme = base.Scheme
+		}
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+	return u
+}
+
+// resolveURLs resolves the document's base, canonical and page URLs against
+// the document URL. The first <link rel="canonical"> is used, or else the
+// og:url.
+func (doc *Document) resolveURLs() {
+	var base *url.URL
+	if doc.URL != nil {
+		base, _ = url.Parse(doc.URL.String())
+	}
+
+	doc.BaseURL = nil
+	if u := resolveURL(base, doc.baseHref); u != nil {
+		// NewURL normalizes the URL in place, so normalize a copy
+		cp := *u
+		doc.BaseURL = normurl.NewURL(&cp, nil)
+		base = u
+	}
+
+	doc.CanonicalURL = nil
+	for _, ref := range doc.canonicalRefs {
+		if u := resolveURL(base, ref); u != nil {
+			doc.CanonicalURL = normurl.NewURL(u, nil)
+			break
+		}
+	}
+
+	// Page links are usually relative, so resolve them against the canonical
+	// URL when the document URL isn't known
+	if base == nil && doc.CanonicalURL != nil {
+		base, _ = url.Parse(doc.CanonicalURL.String())
+	}
+	doc.resolvePageURLs(base)
+}
@@ -0,0 +1,91 @@
+package boilerpipe
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+)
+
+const canonicalTestHTML = `<html>
+<head>
+<base href="/news/">
+<link rel="canonical" href="2019/story.html?utm_source=feed#top">
+<meta property="og:url" content="https://example.com/og-url">
+<meta property="og:site_name" content="Example News">
+<script type="application/ld+json">
+{
+  "@type": "NewsArticle",
+  "publisher": {"@type": "Organization", "name": "Example Media", "url": "https://example.com/"}
+}
+</script>
+</head>
+<body><p>Hello world</p></body>
+</html>`
+
+func TestCanonicalURL(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(canonicalTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The relative canonical URL can't be resolved without the document URL
+	if doc.CanonicalURL == nil || doc.CanonicalURL.String() != "https://example.com/og-url" {
+		t.Errorf("expected og:url canonical URL but got %v", doc.CanonicalURL)
+	}
+	if doc.BaseURL != nil {
+		t.Errorf("expected no base URL but got %v", doc.BaseURL)
+	}
+
+	u, err := normurl.Parse("https://example.com/section/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetURL(u)
+
+	if exp := "https://example.com/news/2019/story.html"; doc.CanonicalURL == nil || doc.CanonicalURL.String() != exp {
+		t.Errorf("expected canonical URL '%s' but got %v", exp, doc.CanonicalURL)
+	}
+	if exp := "https://example.com/news"; doc.BaseURL == nil || doc.BaseURL.String() != exp {
+		t.Errorf("expected base URL '%s' but got %v", exp, doc.BaseURL)
+	}
+
+	if doc.SiteName != "Example News" {
+		t.Errorf("expected site name 'Example News' but got '%s'", doc.SiteName)
+	}
+	if doc.Publisher == nil || doc.Publisher.Name != "Example Media" {
+		t.Errorf("expected publisher 'Example Media' but got %+v", doc.Publisher)
+	}
+}
+
+var resolveURLTestData = []struct {
+	base, ref, exp string
+}{
+	{"", "https://example.com/a/", "https://example.com/a"},
+	{"", "//example.com/a", "http://example.com/a"},
+	{"", "/a", ""},
+	{"https://example.com/a/", "b", "https://example.com/a/b"},
+	{"https://example.com/a/b", "//cdn.example.com/c", "https://cdn.example.com/c"},
+	{"", "mailto:jane@example.com", ""},
+	{"https://example.com/a/b", "../c", "https://example.com/c"},
+	{"https://example.com/a/b", "javascript:void(0)", ""},
+	{"https://example.com/a/b", " ", ""},
+}
+
+func TestResolveURL(t *testing.T) {
+	for _, test := range resolveURLTestData {
+		var base *url.URL
+		if test.base != "" {
+			base, _ = url.Parse(test.base)
+		}
+
+		act := ""
+		if u := resolveURL(base, test.ref); u != nil {
+			act = normurl.NewURL(u, nil).String()
+		}
+		if act != test.exp {
+			t.Errorf("%s + %s: expected '%s' but got '%s'", test.base, test.ref, test.exp, act)
+		}
+	}
+}
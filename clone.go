@@ -0,0 +1,108 @@
+package boilerpipe
+
+import (
+	"github.com/jlubawy/go-boilerpipe/normurl"
+)
+
+// Clone returns a deep copy of the text block, including its labels.
+func (tb *TextBlock) Clone() *TextBlock {
+	cp := *tb
+	cp.labelMap = make(map[Label]int, len(tb.labelMap))
+	for label, count := range tb.labelMap {
+		cp.labelMap[label] = count
+	}
+	return &cp
+}
+
+// Clone returns a deep copy of the document, so that the copy is unchanged
+// when the document is processed by filters or merged with other pages. URLs
+// are shared since they're never modified, and the copy isn't traced.
+func (doc *Document) Clone() *Document {
+	cp := *doc
+	cp.Trace = nil
+
+	if doc.Authors != nil {
+		cp.Authors = make([]Author, len(doc.Authors))
+		for i, a := range doc.Authors {
+			a.Sources = append([]AuthorSource(nil), a.Sources...)
+			cp.Authors[i] = a
+		}
+	}
+	cp.DateCandidates = append([]DateCandidate(nil), doc.DateCandidates...)
+	cp.PageURLs = append([]*normurl.URL(nil), doc.PageURLs...)
+	if doc.Publisher != nil {
+		publisher := *doc.Publisher
+		cp.Publisher = &publisher
+	}
+	cp.Metadata = doc.Metadata.clone()
+	cp.LinkedData = doc.LinkedData.clone()
+	cp.Microdata = doc.Microdata.clone()
+	cp.Comments = append([]Comment(nil), doc.Comments...)
+
+	if doc.Tables != nil {
+		cp.Tables = make([]*Table, len(doc.Tables))
+		for i, t := range doc.Tables {
+			cp.Tables[i] = t.clone()
+		}
+	}
+
+	if doc.TextBlocks != nil {
+		cp.TextBlocks = make([]*TextBlock, len(doc.TextBlocks))
+		for i, tb := range doc.TextBlocks {
+			cp.TextBlocks[i] = tb.Clone()
+		}
+	}
+
+	cp.canonicalRefs = append([]string(nil), doc.canonicalRefs...)
+	cp.nextPageRefs = append([]string(nil), doc.nextPageRefs...)
+	if doc.pageRefs != nil {
+		cp.pageRefs = make(map[int]string, len(doc.pageRefs))
+		for n, ref := range doc.pageRefs {
+			cp.pageRefs[n] = ref
+		}
+	}
+
+	return &cp
+}
+
+func (m Metadata) clone() Metadata {
+	m.Authors = append([]string(nil), m.Authors...)
+	m.Keywords = append([]string(nil), m.Keywords...)
+	if m.Properties != nil {
+		properties := make(map[string][]string, len(m.Properties))
+		for key, values := range m.Properties {
+			properties[key] = append([]string(nil), values...)
+		}
+		m.Properties = properties
+	}
+	return m
+}
+
+func (a *SchemaArticle) clone() *SchemaArticle {
+	if a == nil {
+		return nil
+	}
+	cp := *a
+	cp.Types = append([]string(nil), a.Types...)
+	cp.Authors = append([]SchemaEntity(nil), a.Authors...)
+	if a.Publisher != nil {
+		publisher := *a.Publisher
+		cp.Publisher = &publisher
+	}
+	cp.Images = append([]string(nil), a.Images...)
+	cp.Sections = append([]string(nil), a.Sections...)
+	cp.Keywords = append([]string(nil), a.Keywords...)
+	return &cp
+}
+
+func (t *Table) clone() *Table {
+	cp := *t
+	cp.Header = append([]string(nil), t.Header...)
+	if t.Rows != nil {
+		cp.Rows = make([][]string, len(t.Rows))
+		for i, row := range t.Rows {
+			cp.Rows[i] = append([]string(nil), row...)
+		}
+	}
+	return &cp
+}
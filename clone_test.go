@@ -0,0 +1,45 @@
+package boilerpipe
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestDocumentClone(t *testing.T) {
+	f, err := os.Open("testdata/2.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	doc, err := ParseDocument(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cp := doc.Clone()
+	ArticlePipeline.Process(cp)
+	cp.MergePage(cp.Clone())
+	for key := range cp.Metadata.Properties {
+		cp.Metadata.Properties[key][0] = "Changed"
+	}
+	cp.TextBlocks[0].AddLabels(LabelComment)
+
+	act, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(act) != string(exp) {
+		t.Error("expected document to be unchanged by changes to its clone")
+	}
+
+	if doc.TextBlocks[0].HasLabel(LabelComment) {
+		t.Error("expected labels of the text block to be copied")
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -10,7 +11,6 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"os"
-	"time"
 
 	"github.com/jlubawy/go-boilerpipe"
 	"github.com/jlubawy/go-boilerpipe/normurl"
@@ -20,6 +20,8 @@ import (
 
 var (
 	FlagPrettyPrint bool
+	FlagMaxPages    int
+	FlagExplain     bool
 )
 
 var commandExtract = &Command{
@@ -32,6 +34,8 @@ func extractFunc(args []string) {
 	flagset := flag.NewFlagSet("", flag.ExitOnError)
 	flagset.Usage = extractHelpFunc
 	flagset.BoolVar(&FlagPrettyPrint, "pretty-print", false, "pretty print JSON output")
+	flagset.IntVar(&FlagMaxPages, "pages", 0, "maximum number of following pages of a URL to fetch and merge")
+	flagset.BoolVar(&FlagExplain, "explain", false, "explain why each filter changed the text blocks instead of printing JSON")
 	flagset.Parse(args)
 
 	if len(flagset.Args()) > 1 {
@@ -93,7 +97,16 @@ func NewClient() *http.Client {
 }
 
 func httpGet(urlStr string) (io.ReadCloser, error) {
-	resp, err := NewClient().Get(urlStr)
+	return httpGetContext(context.Background(), urlStr)
+}
+
+func httpGetContext(ctx context.Context, urlStr string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := NewClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -107,23 +120,45 @@ func httpGet(urlStr string) (io.ReadCloser, error) {
 
 func extract(r io.Reader, u *normurl.URL) {
 	var (
-		doc *boilerpipe.Document
 		b   []byte
 		err error
 	)
 
+	opts := []boilerpipe.Option{boilerpipe.WithURL(u)}
+	if u != nil && FlagMaxPages > 0 {
+		fetcher := boilerpipe.FetcherFunc(func(u *normurl.URL) (io.ReadCloser, error) {
+			return httpGet(u.String())
+		})
+		opts = append(opts, boilerpipe.WithPages(fetcher, FlagMaxPages))
+	}
+
+	trace := &boilerpipe.Trace{}
+	if FlagExplain {
+		opts = append(opts, boilerpipe.WithTrace(trace))
+	}
+
 	// Get text document and extract content
-	doc, err = boilerpipe.ParseDocument(r)
+	article, err := boilerpipe.Extract(r, opts...)
 	if err != nil {
-		fatalf("Error creating new document: %v\n", err)
+		var parseErr *boilerpipe.ParseError
+		if !errors.As(err, &parseErr) {
+			fatalf("Error extracting document: %v\n", err)
+		}
+		// Extract what was parsed of the document
+		fmt.Fprintf(os.Stderr, "Error parsing document: %v\n", err)
+	}
+
+	if FlagExplain {
+		if _, err := trace.WriteTo(os.Stdout); err != nil {
+			fatalf("Error writing explanation: %v\n", err)
+		}
+		return
 	}
-	boilerpipe.ArticlePipeline.Process(doc)
 
-	jsonDoc := NewJSONDocument(doc)
 	if FlagPrettyPrint {
-		b, err = json.MarshalIndent(jsonDoc, "", "  ")
+		b, err = json.MarshalIndent(article, "", "  ")
 	} else {
-		b, err = json.Marshal(jsonDoc)
+		b, err = json.Marshal(article)
 	}
 	if err != nil {
 		fatalf("Error encoding JSON: %v\n", err)
@@ -133,29 +168,20 @@ func extract(r io.Reader, u *normurl.URL) {
 }
 
 func extractHelpFunc() {
-	fmt.Fprint(os.Stderr, `usage: boilerpipe extract [document path]
+	fmt.Fprint(os.Stderr, `usage: boilerpipe extract [-pretty-print] [-pages n] [-explain] [document path]
 
 Extract extracts text from the provided HTML document and prints the results to
 stdout.
 
 If no argument is provided the document is read from stdin, else the argument is
 parsed first as a URL and then a filename.
-`)
-	os.Exit(1)
-}
 
-type jsonDocument struct {
-	Title   string    `json:"title"`
-	Author  string    `json:"author"`
-	Date    time.Time `json:"date"`
-	Content string    `json:"content"`
-}
+If the document is a URL split across several pages, -pages fetches up to n
+following pages and merges their content into the results.
 
-func NewJSONDocument(doc *boilerpipe.Document) *jsonDocument {
-	return &jsonDocument{
-		Title:   doc.Title,
-		Author:  doc.Author,
-		Date:    doc.Date,
-		Content: doc.Content(),
-	}
+With -explain, the text blocks each filter of the pipeline changed are printed
+along with why, such as the rule which classified a block, instead of the
+results.
+`)
+	os.Exit(1)
 }
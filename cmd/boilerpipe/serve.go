@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -22,12 +23,29 @@ var commandServe = &Command{
 	HelpFunc:    serveHelpFunc,
 }
 
+// serveParseOptions are the limits of the documents parsed by the server,
+// since the URLs it's given are untrusted.
+var serveParseOptions = boilerpipe.ParseOptions{
+	MaxBytes:      10 << 20,
+	MaxTokens:     1000000,
+	MaxDepth:      512,
+	MaxTextBlocks: 50000,
+}
+
+// serveTimeout is the maximum time to fetch and extract a document.
+var serveTimeout = 30 * time.Second
+
 func serveFunc(args []string) {
 	var port uint
 
 	flagset := flag.NewFlagSet("", flag.ExitOnError)
 	flagset.Usage = serveHelpFunc
 	flagset.UintVar(&port, "port", 8080, "TCP port to listen on")
+	flagset.Int64Var(&serveParseOptions.MaxBytes, "max-bytes", serveParseOptions.MaxBytes, "maximum size of a document in bytes")
+	flagset.IntVar(&serveParseOptions.MaxTokens, "max-tokens", serveParseOptions.MaxTokens, "maximum number of HTML tokens in a document")
+	flagset.IntVar(&serveParseOptions.MaxDepth, "max-depth", serveParseOptions.MaxDepth, "maximum nesting depth of a document's elements")
+	flagset.IntVar(&serveParseOptions.MaxTextBlocks, "max-text-blocks", serveParseOptions.MaxTextBlocks, "maximum number of text blocks in a document")
+	flagset.DurationVar(&serveTimeout, "timeout", serveTimeout, "maximum time to fetch and extract a document")
 	flagset.Parse(args)
 
 	if len(flag.Args()) > 0 {
@@ -48,9 +66,14 @@ func serveFunc(args []string) {
 }
 
 func serveHelpFunc() {
-	fmt.Fprint(os.Stderr, `usage: boilerpipe serve [-port=8080]
+	fmt.Fprint(os.Stderr, `usage: boilerpipe serve [-port=8080] [-max-bytes n] [-max-tokens n]
+                        [-max-depth n] [-max-text-blocks n] [-timeout d]
 
 Serve starts an HTTP server listening on the provided port.
+
+Documents which exceed any of the limits are rejected. A limit of 0 means there
+is no limit. Documents which take longer than the timeout to fetch and extract
+are also rejected.
 `)
 	os.Exit(1)
 }
@@ -115,27 +138,38 @@ func extractHandler(w http.ResponseWriter, req *http.Request) (int, error) {
 		return http.StatusBadRequest, err
 	}
 
-	rc, err := httpGet(rawurl)
+	ctx, cancel := context.WithTimeout(req.Context(), serveTimeout)
+	defer cancel()
+
+	rc, err := httpGetContext(ctx, rawurl)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 	defer rc.Close()
 
-	pipelineFilter := &LoggingPipeline{
-		Pipeline:   boilerpipe.ArticlePipeline,
-		LogEntries: make([]LogEntry, 0),
+	pipelineFilter := &boilerpipe.TracingPipeline{
+		Pipeline: boilerpipe.ArticlePipeline,
 	}
 
-	doc, err := boilerpipe.ParseDocument(rc)
-	if err != nil {
-		return http.StatusInternalServerError, err
+	// Show what was parsed of the document along with any read error, but
+	// reject documents which exceed the limits
+	doc, parseErr := boilerpipe.ParseDocumentContext(ctx, rc, &serveParseOptions)
+	if errors.Is(parseErr, boilerpipe.ErrLimitExceeded) {
+		return http.StatusRequestEntityTooLarge, parseErr
+	}
+	if errors.Is(parseErr, boilerpipe.ErrCanceled) {
+		return http.StatusGatewayTimeout, parseErr
+	}
+	doc.SetURL(u)
+	if _, err := pipelineFilter.ProcessContext(ctx, doc); err != nil {
+		return http.StatusGatewayTimeout, err
 	}
-	pipelineFilter.Process(doc)
 
 	data := map[string]interface{}{
 		"Content":        StringToHTML(doc.Content()),
 		"Date":           doc.Date.Format("January 2, 2006"),
 		"Doc":            doc,
+		"ParseError":     parseErr,
 		"pipelineFilter": pipelineFilter,
 		"RawURL":         rawurl,
 		"url":            u.String(),
@@ -169,45 +203,15 @@ func StringToHTML(s string) htemp.HTML {
 	return htemp.HTML(buf.String())
 }
 
-type LogEntry struct {
-	FilterName string
-	Document   boilerpipe.Document
-	HasChanged bool
-}
-
-type LoggingPipeline struct {
-	Pipeline   *boilerpipe.Pipeline
-	LogEntries []LogEntry
-}
-
-var _ boilerpipe.Filter = (*LoggingPipeline)(nil)
-
-func (pipeline *LoggingPipeline) Name() string { return pipeline.Pipeline.Name() }
-
-func (pipeline *LoggingPipeline) Process(doc *boilerpipe.Document) (hasChanged bool) {
-	pipeline.LogEntries = append(pipeline.LogEntries, LogEntry{
-		FilterName: fmt.Sprintf("%s.000", pipeline.Pipeline.Name()),
-		Document:   *doc,
-		HasChanged: false,
-	})
-
-	for i, filter := range pipeline.Pipeline.Filters {
-		hasChanged = filter.Process(doc) || hasChanged
+var templateMap = make(map[string]*htemp.Template)
 
-		pipeline.LogEntries = append(pipeline.LogEntries, LogEntry{
-			FilterName: fmt.Sprintf("%s.%03d.%s", pipeline.Pipeline.Name(), i+1, filter.Name()),
-			Document:   *doc,
-			HasChanged: hasChanged,
-		})
-	}
-	return
+var templateFuncs = htemp.FuncMap{
+	"inc": func(i int) int { return i + 1 },
 }
 
-var templateMap = make(map[string]*htemp.Template)
-
 func ParseTemplates() error {
 	for name, s := range templStrs {
-		rootTempl, err := htemp.New("").Parse(templRootStr)
+		rootTempl, err := htemp.New("").Funcs(templateFuncs).Parse(templRootStr)
 		if err != nil {
 			return err
 		}
@@ -293,11 +297,18 @@ var templStrs = map[string]string{
 {{end}}`,
 
 	"extract": `{{define "Body"}}<div class="container">
+{{if .ParseError}}
+  <div class="row">
+    <div class="col">
+      <div class="alert alert-warning" role="alert">The document was only partially parsed: {{.ParseError}}</div>
+    </div><!-- col -->
+  </div><!-- row -->
+{{end}}
   <div class="row">
     <div class="col">
       <dl class="row">
         <dt class="col-sm-1">Title</dt>
-        <dd class="col-sm-11"><a href="{{.RawURL}}" target="_blank">{{.Doc.Title}}</a></dd>
+        <dd class="col-sm-11"><a href="{{.RawURL}}" target="_blank">{{if .Doc.CleanTitle}}{{.Doc.CleanTitle}}{{else}}{{.Doc.Title}}{{end}}</a></dd>
 
         <dt class="col-sm-1">Date</dt>
         <dd class="col-sm-11">{{.Date}}</dd>
@@ -315,19 +326,19 @@ var templStrs = map[string]string{
   <div class="row">
     <div class="col">
       <div id="accordion">
-{{range $logIdx, $logEntry := $.pipelineFilter.LogEntries}}
+{{with $.pipelineFilter.Start}}
         <div class="card">
-          <div class="card-header" id="heading-{{$logIdx}}">
+          <div class="card-header" id="heading-start">
             <h5 class="mb-0">
-              <button class="btn btn-link" data-toggle="collapse" data-target="#collapse-{{$logIdx}}" aria-expanded="false" aria-controls="#collapse-{{$logIdx}}">
-                <i class="icon {{if $logEntry.HasChanged}}ion-checkmark{{else}}ion-close{{end}}"></i> {{$logEntry.FilterName}} ({{len $logEntry.Document.TextBlocks}})
+              <button class="btn btn-link" data-toggle="collapse" data-target="#collapse-start" aria-expanded="false" aria-controls="#collapse-start">
+                {{$.pipelineFilter.Name}}.000 ({{len .TextBlocks}})
               </button>
             </h5>
           </div>
 
-          <div id="collapse-{{$logIdx}}" class="collapse" aria-labelledby="heading-{{$logIdx}}" data-parent="#accordion">
+          <div id="collapse-start" class="collapse" aria-labelledby="heading-start" data-parent="#accordion">
             <div class="card-body">
-{{range $textBlockIdx, $textBlock := $logEntry.Document.TextBlocks}}
+{{range $textBlockIdx, $textBlock := .TextBlocks}}
               <div class="card">
                 <div class="card-body">
                   <p>{{$textBlockIdx}}: {{.Text}}</p>
@@ -337,6 +348,35 @@ var templStrs = map[string]string{
             </div>
           </div>
         </div>
+{{end}}
+{{range $stepIdx, $step := $.pipelineFilter.Steps}}
+        <div class="card">
+          <div class="card-header" id="heading-{{$stepIdx}}">
+            <h5 class="mb-0">
+              <button class="btn btn-link" data-toggle="collapse" data-target="#collapse-{{$stepIdx}}" aria-expanded="false" aria-controls="#collapse-{{$stepIdx}}">
+                <i class="icon {{if $step.HasChanged}}ion-checkmark{{else}}ion-close{{end}}"></i> {{$.pipelineFilter.Name}}.{{printf "%03d" (inc $stepIdx)}}.{{$step.FilterName}} ({{len $step.Document.TextBlocks}}, {{len $step.Diffs}} changed)
+              </button>
+            </h5>
+          </div>
+
+          <div id="collapse-{{$stepIdx}}" class="collapse" aria-labelledby="heading-{{$stepIdx}}" data-parent="#accordion">
+            <div class="card-body">
+{{range $step.Diffs}}
+              <div class="card">
+                <div class="card-body">
+{{if not .Before}}
+                  <p><span class="badge badge-success">added</span> {{.After.Text}}</p>
+{{else if not .After}}
+                  <p><span class="badge badge-danger">removed</span> {{.Before.Text}}</p>
+{{else}}
+                  <p>{{if .ContentChanged}}<span class="badge {{if .After.IsContent}}badge-success{{else}}badge-secondary{{end}}">{{if .After.IsContent}}content{{else}}boilerplate{{end}}</span> {{end}}{{range .LabelsAdded}}<span class="badge badge-info">+{{.}}</span> {{end}}{{range .LabelsRemoved}}<span class="badge badge-warning">-{{.}}</span> {{end}}{{.After.Text}}</p>
+{{end}}
+                </div>
+              </div>
+{{end}}
+            </div>
+          </div>
+        </div>
 {{end}}
       </div><!-- #accordion -->
     </div><!-- col -->
@@ -0,0 +1,316 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// A Comment is a reader comment found in the comment section of a document.
+type Comment struct {
+	Author string    `json:"author"`
+	Date   time.Time `json:"date"`
+	Text   string    `json:"text"`
+
+	// Parent is the index of the comment this comment replies to, or -1 if
+	// it is not a reply.
+	Parent int `json:"parent"`
+}
+
+var (
+	// reCommentRegion matches the class names and ids of comment sections,
+	// such as "comments", "comment-list" or "disqus_thread".
+	reCommentRegion = regexp.MustCompile(`^(?:[a-z0-9]+[-_])*(?:comments|commentlist|comment[-_]?(?:list|section|area|thread|wrap|wrapper|container)|disqus[-_]thread)(?:[-_](?:list|section|area|wrap|wrapper|container|block|inner))*$`)
+
+	// reCommentItem matches the class names of individual comments.
+	reCommentItem = regexp.MustCompile(`^(?:comment|comment[-_]?(?:item|entry|single)|single[-_]comment)$`)
+
+	// reCommentItemID matches the ids of individual comments, such as
+	// "comment-123".
+	reCommentItemID = regexp.MustCompile(`^(?:li[-_])?comment[-_]\d+$`)
+)
+
+// commentRegionAtoms are the elements which can contain a comment section.
+var commentRegionAtoms = map[atom.Atom]bool{
+	atom.Div:     true,
+	atom.Section: true,
+	atom.Aside:   true,
+	atom.Ol:      true,
+	atom.Ul:      true,
+	atom.Footer:  true,
+}
+
+// commentParagraphAtoms are the elements whose text is separated from the
+// preceding text of a comment.
+var commentParagraphAtoms = map[atom.Atom]bool{
+	atom.P:          true,
+	atom.Div:        true,
+	atom.Li:         true,
+	atom.Blockquote: true,
+	atom.Pre:        true,
+}
+
+// commentField is a field of a comment whose text is being captured.
+type commentField struct {
+	depth int
+	buf   bytes.Buffer
+}
+
+func (f *commentField) capturing() bool { return f.depth != 0 }
+
+// start starts capturing the field at the given depth, discarding the text
+// of any outer element being captured so the most specific element wins.
+func (f *commentField) start(depth int) {
+	f.depth = depth
+	f.buf.Reset()
+}
+
+type commentItem struct {
+	index int
+	depth int
+
+	author  commentField
+	date    commentField
+	text    commentField
+	hasText bool
+
+	// rest is the text which is not part of the author or date, used when
+	// the comment text has no element of its own.
+	rest bytes.Buffer
+	time time.Time
+}
+
+// commentAttrs are the attributes of an element used to find comments.
+type commentAttrs struct {
+	classes  []string
+	id       string
+	itemtype string
+	itemprop []string
+	datetime string
+}
+
+func getCommentAttrs(tok *html.Token) (attrs commentAttrs) {
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "class":
+			attrs.classes = strings.Fields(strings.ToLower(attr.Val))
+		case "id":
+			attrs.id = strings.ToLower(strings.TrimSpace(attr.Val))
+		case "itemtype":
+			attrs.itemtype = attr.Val
+		case "itemprop":
+			attrs.itemprop = strings.Fields(attr.Val)
+		case "datetime":
+			attrs.datetime = attr.Val
+		}
+	}
+	return
+}
+
+// has returns true if the class names or itemprop contain any of the names.
+func (attrs *commentAttrs) has(names ...string) bool {
+	for _, name := range names {
+		for _, c := range attrs.classes {
+			if c == name {
+				return true
+			}
+		}
+		for _, p := range attrs.itemprop {
+			if p == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (attrs *commentAttrs) isRegion(a atom.Atom) bool {
+	if !commentRegionAtoms[a] {
+		return false
+	}
+	if reCommentRegion.MatchString(attrs.id) {
+		return true
+	}
+	for _, c := range attrs.classes {
+		if reCommentRegion.MatchString(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (attrs *commentAttrs) isItem() bool {
+	if strings.HasSuffix(attrs.itemtype, "schema.org/Comment") || reCommentItemID.MatchString(attrs.id) {
+		return true
+	}
+	for _, c := range attrs.classes {
+		if reCommentItem.MatchString(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// comments collects the comments of a document while it's parsed.
+type comments struct {
+	regionDepth int
+	items       []*commentItem
+	comments    []Comment
+
+	// hasText is true if text has been added to the current text block
+	// within the comment section.
+	hasText bool
+}
+
+// inComments returns true if the element being parsed is part of the comment
+// section or of a comment.
+func (c *comments) inComments() bool {
+	return c.regionDepth != 0 || len(c.items) > 0
+}
+
+// CommentStartElement finds the comment section, the comments and their
+// fields from the element at the given depth.
+func (h *contentHandler) CommentStartElement(tok *html.Token, depth int) {
+	c := &h.comments
+	attrs := getCommentAttrs(tok)
+
+	if c.regionDepth == 0 && attrs.isRegion(tok.DataAtom) {
+		c.regionDepth = depth
+	}
+
+	if attrs.isItem() && (c.regionDepth != 0 || attrs.itemtype != "") {
+		parent := -1
+		if len(c.items) > 0 {
+			parent = c.items[len(c.items)-1].index
+		}
+		c.items = append(c.items, &commentItem{
+			index: len(c.comments),
+			depth: depth,
+		})
+		c.comments = append(c.comments, Comment{Parent: parent})
+		return
+	}
+
+	if len(c.items) == 0 {
+		return
+	}
+	item := c.items[len(c.items)-1]
+
+	// Separate the text of paragraphs
+	if commentParagraphAtoms[tok.DataAtom] {
+		h.CommentText(" ")
+	}
+
+	switch {
+	case tok.DataAtom == atom.Time && attrs.datetime != "":
+		if t, ok := ParseDate(attrs.datetime); ok && item.time.IsZero() {
+			item.time = t
+		}
+	case attrs.has("fn", "nickname", "username", "user-name", "comment-author-name", "name"):
+		item.author.start(depth)
+	case attrs.has("author", "comment-author", "commenter", "comment-user", "user") && !item.author.capturing():
+		item.author.start(depth)
+	case attrs.has("date", "time", "timestamp", "published", "comment-date", "comment-time", "datecreated", "datepublished"):
+		item.date.start(depth)
+	case attrs.has("text", "content", "message", "body", "comment-content", "comment-text", "comment-message", "comment-body"):
+		item.text.start(depth)
+		item.hasText = true
+	}
+}
+
+// CommentText adds text to the innermost comment being collected.
+func (h *contentHandler) CommentText(text string) {
+	c := &h.comments
+	if c.inComments() {
+		c.hasText = true
+	}
+	if len(c.items) == 0 {
+		return
+	}
+
+	item := c.items[len(c.items)-1]
+	switch {
+	case item.author.capturing():
+		item.author.buf.WriteString(text)
+	case item.date.capturing():
+		item.date.buf.WriteString(text)
+	default:
+		if item.text.capturing() {
+			item.text.buf.WriteString(text)
+		}
+		item.rest.WriteString(text)
+	}
+}
+
+// CommentEndElement completes the comment fields, comments and comment
+// section of the element at the given depth, including any that were left
+// open by descendants.
+func (h *contentHandler) CommentEndElement(depth int) {
+	c := &h.comments
+
+	for len(c.items) > 0 {
+		item := c.items[len(c.items)-1]
+		for _, f := range []*commentField{&item.author, &item.date, &item.text} {
+			if f.depth >= depth {
+				f.depth = 0
+			}
+		}
+
+		if item.depth < depth {
+			break
+		}
+		c.items = c.items[:len(c.items)-1]
+		c.finish(item)
+	}
+
+	if c.regionDepth != 0 && depth <= c.regionDepth {
+		c.regionDepth = 0
+	}
+}
+
+// finish sets the fields of a comment once all of its text has been
+// collected.
+func (c *comments) finish(item *commentItem) {
+	comment := &c.comments[item.index]
+
+	comment.Author = cleanAuthorName(item.author.buf.String())
+	comment.Author = strings.TrimSpace(strings.TrimSuffix(comment.Author, " says"))
+
+	comment.Date = item.time
+	if comment.Date.IsZero() {
+		if t, ok := ParseDate(strings.TrimSpace(item.date.buf.String())); ok {
+			comment.Date = t
+		}
+	}
+
+	text := item.rest.String()
+	if item.hasText {
+		text = item.text.buf.String()
+	}
+	comment.Text = strings.Join(strings.Fields(text), " ")
+}
+
+// documentComments returns the comments which have text. Replies to comments
+// without text become replies to their nearest ancestor with text.
+func (c *comments) documentComments() (comments []Comment) {
+	index := make(map[int]int)
+	for i, comment := range c.comments {
+		if comment.Text == "" {
+			index[i] = -1
+			if comment.Parent != -1 {
+				index[i] = index[comment.Parent]
+			}
+			continue
+		}
+		if comment.Parent != -1 {
+			comment.Parent = index[comment.Parent]
+		}
+		index[i] = len(comments)
+		comments = append(comments, comment)
+	}
+	return
+}
@@ -0,0 +1,119 @@
+package boilerpipe
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const commentsTestHTML = `<html>
+<body>
+<article>
+<h1>The headline</h1>
+<p>By Jane Doe</p>
+<p>This is the text of the article, which is long enough to be the content of the document.</p>
+</article>
+<div id="comments" class="comments-area">
+<h2 class="comments-title">2 thoughts on "The headline"</h2>
+<ol class="comment-list">
+<li id="comment-1" class="comment even thread-even depth-1">
+<article id="div-comment-1" class="comment-body">
+<footer class="comment-meta">
+<div class="comment-author vcard"><b class="fn">John Smith</b> <span class="says">says:</span></div>
+<div class="comment-metadata"><a href="#comment-1"><time datetime="2019-03-28T13:55:00+00:00">March 28, 2019 at 1:55 pm</time></a></div>
+</footer>
+<div class="comment-content"><p>Great article!</p><p>Thanks for writing it.</p></div>
+</article>
+<ol class="children">
+<li id="comment-2" class="comment odd alt depth-2">
+<article id="div-comment-2" class="comment-body">
+<footer class="comment-meta">
+<div class="comment-author vcard"><b class="fn">Jane Doe</b> <span class="says">says:</span></div>
+<div class="comment-metadata"><span class="date">March 29, 2019</span></div>
+</footer>
+<div class="comment-content"><p>Thank you, John.</p></div>
+</article>
+</li>
+</ol>
+</li>
+</ol>
+</div>
+</body>
+</html>`
+
+func TestComments(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(commentsTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []Comment{
+		{
+			Author: "John Smith",
+			Date:   time.Date(2019, time.March, 28, 13, 55, 0, 0, time.UTC),
+			Text:   "Great article! Thanks for writing it.",
+			Parent: -1,
+		},
+		{
+			Author: "Jane Doe",
+			Date:   time.Date(2019, time.March, 29, 0, 0, 0, 0, time.UTC),
+			Text:   "Thank you, John.",
+			Parent: 0,
+		},
+	}
+
+	if len(doc.Comments) != len(exp) {
+		t.Fatalf("expected %d comments but got %+v", len(exp), doc.Comments)
+	}
+	for i := range exp {
+		act := doc.Comments[i]
+		if act.Author != exp[i].Author || !act.Date.Equal(exp[i].Date) || act.Text != exp[i].Text || act.Parent != exp[i].Parent {
+			t.Errorf("expected %+v but got %+v", exp[i], act)
+		}
+	}
+
+	// The comment dates and authors are not the document's
+	if !doc.Date.IsZero() {
+		t.Errorf("expected no document date but got %v", doc.Date)
+	}
+	if len(doc.Authors) != 1 {
+		t.Errorf("expected 1 author but got %+v", doc.Authors)
+	}
+
+	inComments := false
+	for _, tb := range doc.TextBlocks {
+		if strings.Contains(tb.Text, "thoughts on") {
+			inComments = true
+		}
+		if inComments != tb.HasLabel(LabelComment) {
+			t.Errorf("expected block '%s' to have LabelComment %v", tb.Text, inComments)
+		}
+	}
+}
+
+const microdataCommentsTestHTML = `<html>
+<body>
+<p>Text</p>
+<div itemscope itemtype="https://schema.org/Comment">
+<span itemprop="author">Li Na</span>
+<div itemprop="text">First!</div>
+</div>
+<div itemscope itemtype="https://schema.org/Comment">
+<span itemprop="author">Nobody</span>
+</div>
+</body>
+</html>`
+
+func TestMicrodataComments(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(microdataCommentsTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Comments) != 1 {
+		t.Fatalf("expected 1 comment but got %+v", doc.Comments)
+	}
+	if c := doc.Comments[0]; c.Author != "Li Na" || c.Text != "First!" || c.Parent != -1 {
+		t.Errorf("unexpected comment %+v", c)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"regexp"
 	"strings"
-	"time"
 	"unicode"
 
 	"golang.org/x/net/html"
@@ -12,27 +11,53 @@ import (
 )
 
 type atomStack struct {
-	s []atom.Atom
+	s     []atom.Atom
+	names []string
+
+	// counts are the number of open elements by name, to avoid searching the
+	// stack for elements which aren't open.
+	counts map[string]int
+
+	// labels are the lengths of the label stack when each element was
+	// started, so the labels pushed by an element are popped when it ends.
+	labels []int
 }
 
 func newAtomStack() *atomStack {
 	return &atomStack{
-		s: make([]atom.Atom, 0),
+		s:      make([]atom.Atom, 0),
+		counts: make(map[string]int),
 	}
 }
 
-func (stack *atomStack) Push(a atom.Atom) *atomStack {
+func (stack *atomStack) Push(a atom.Atom, name string, numLabels int) *atomStack {
 	stack.s = append(stack.s, a)
+	stack.names = append(stack.names, name)
+	stack.labels = append(stack.labels, numLabels)
+	stack.counts[name]++
 	return stack
 }
 
-func (stack *atomStack) Pop() atom.Atom {
+// Pop ends the innermost open element, returning the length of the label
+// stack when it was started.
+func (stack *atomStack) Pop() int {
 	if len(stack.s) == 0 {
-		return atom.Atom(0)
+		return 0
 	}
-	el := stack.s[len(stack.s)-1]
+	numLabels := stack.labels[len(stack.labels)-1]
+	stack.counts[stack.names[len(stack.names)-1]]--
 	stack.s = stack.s[:len(stack.s)-1]
-	return el
+	stack.names = stack.names[:len(stack.names)-1]
+	stack.labels = stack.labels[:len(stack.labels)-1]
+	return numLabels
+}
+
+// Top returns the innermost open element, or 0 if there is none.
+func (stack *atomStack) Top() atom.Atom {
+	if len(stack.s) == 0 {
+		return atom.Atom(0)
+	}
+	return stack.s[len(stack.s)-1]
 }
 
 const (
@@ -42,12 +67,13 @@ const (
 
 type contentHandler struct {
 	title string
-	time  time.Time
+	dates []DateCandidate
 
 	tokenBuffer *bytes.Buffer
 	textBuffer  *bytes.Buffer
 
 	depthBody       int
+	bodyStarted     bool
 	depthAnchor     int
 	depthIgnoreable int
 
@@ -75,6 +101,25 @@ type contentHandler struct {
 
 	inLinkedDataJSON bool
 	linkedDataJSON   []string
+
+	metadata map[string][]string
+
+	microdata *microdata
+
+	authorLinkDepth int
+	authorLinkText  bytes.Buffer
+	authorLinks     []string
+
+	lang string
+
+	links    map[string][]string
+	baseHref string
+
+	comments comments
+
+	pagination pagination
+
+	tables tables
 }
 
 func newContentHandler() *contentHandler {
@@ -91,24 +136,38 @@ func newContentHandler() *contentHandler {
 		atomStack: newAtomStack(),
 
 		linkedDataJSON: make([]string, 0),
+
+		metadata: make(map[string][]string),
+
+		microdata: newMicrodata(),
+
+		links: make(map[string][]string),
 	}
 }
 
 func (h *contentHandler) StartElement(tok *html.Token) {
-	h.atomStack.Push(tok.DataAtom)
+	h.atomStack.Push(tok.DataAtom, tok.Data, h.labelStack.Len())
+	h.MicrodataStartElement(tok, len(h.atomStack.s))
+	h.AuthorLinkStart(tok, len(h.atomStack.s))
+	h.CommentStartElement(tok, len(h.atomStack.s))
+	h.PaginationStartElement(tok, len(h.atomStack.s))
+	h.TableStartElement(tok, len(h.atomStack.s))
+
+	if tok.DataAtom == atom.Html {
+		for _, attr := range tok.Attr {
+			if attr.Key == "lang" || attr.Key == "xml:lang" {
+				h.SetLanguage(attr.Val)
+			}
+		}
+	}
 
 	ta, ok := tagActionMap[tok.DataAtom]
 	if ok {
 		switch ta.(type) {
 		case *tagActionTime:
-			for _, attr := range tok.Attr {
-				if attr.Key == "datetime" {
-					t, err := time.Parse(time.RFC3339, attr.Val)
-					if err == nil {
-						h.time = t
-					}
-					break
-				}
+			// The dates of comments are not dates of the document
+			if !h.comments.inComments() {
+				h.TimeElement(tok)
 			}
 		}
 
@@ -126,10 +185,13 @@ func (h *contentHandler) StartElement(tok *html.Token) {
 }
 
 func (h *contentHandler) EndElement(tok *html.Token) {
-	pa := h.atomStack.Pop()
-	if pa != tok.DataAtom {
-		return // malformed HTML, missing closing tag
-	}
+	h.MicrodataEndElement(len(h.atomStack.s))
+	h.AuthorLinkEnd(len(h.atomStack.s))
+	h.CommentEndElement(len(h.atomStack.s))
+	h.PaginationEndElement(len(h.atomStack.s))
+	h.TableEndElement(len(h.atomStack.s))
+
+	numLabels := h.atomStack.Pop()
 
 	ta, ok := tagActionMap[tok.DataAtom]
 	if ok {
@@ -148,7 +210,10 @@ func (h *contentHandler) EndElement(tok *html.Token) {
 
 	h.lastEndTag = tok.Data
 
-	h.labelStack.Pop()
+	// The labels of the element may have been popped by a flush already
+	for h.labelStack.Len() > numLabels {
+		h.labelStack.Pop()
+	}
 }
 
 type spaceRemover struct {
@@ -186,6 +251,16 @@ func (h *contentHandler) TextToken(tok *html.Token) {
 		h.flush = false
 	}
 
+	// The features collect the text of ignorable elements too (e.g. a table
+	// in a figure), but not the source of scripts and styles
+	if a := h.atomStack.Top(); a != atom.Script && a != atom.Style {
+		h.MicrodataText(tok.Data)
+		h.AuthorLinkText(tok.Data)
+		h.CommentText(tok.Data)
+		h.PaginationText(tok.Data)
+		h.TableText(tok.Data)
+	}
+
 	if h.depthIgnoreable != 0 {
 		return
 	}
@@ -285,6 +360,8 @@ func (h *contentHandler) FlushBlock() {
 		}
 	}
 
+	// The words are counted with boilerpipe's tokenizer whatever the language
+	// since the thresholds of the classifiers are tuned for it
 	tokens := tokenize(h.tokenBuffer)
 
 	const maxLineLength = 80
@@ -367,6 +444,9 @@ func (h *contentHandler) FlushBlock() {
 		//}
 
 		tb.AddLabels(h.labelStack.PopAll()...)
+		if h.comments.hasText {
+			tb.AddLabels(LabelComment)
+		}
 
 		h.textBlocks = append(h.textBlocks, tb)
 
@@ -376,6 +456,7 @@ func (h *contentHandler) FlushBlock() {
 
 	h.textBuffer.Reset()
 	h.tokenBuffer.Reset()
+	h.comments.hasText = false
 
 	h.depthBlockTag = -1
 }
@@ -445,6 +526,7 @@ type tagActionBody struct{}
 func (ta *tagActionBody) Start(h *contentHandler) bool {
 	h.FlushBlock()
 	h.depthBody++
+	h.bodyStarted = true
 	return false
 }
 func (*tagActionBody) End(h *contentHandler) bool {
@@ -501,6 +583,7 @@ var tagActionMap = map[atom.Atom]tagAction{
 	atom.Applet:     &tagActionIgnorable{},
 	atom.Figcaption: &tagActionIgnorable{},
 	atom.Figure:     &tagActionIgnorable{},
+	atom.Iframe:     &tagActionIgnorable{},
 	atom.Noscript:   &tagActionIgnorable{},
 	atom.Object:     &tagActionIgnorable{},
 	atom.Option:     &tagActionIgnorable{},
@@ -551,24 +634,3 @@ var tagActionMap = map[atom.Atom]tagAction{
 
 	atom.Time: &tagActionTime{},
 }
-
-// Checks if the tag should be self-closing.
-func shouldBeSelfClosingTag(tag atom.Atom) bool {
-	switch tag {
-	case atom.Area,
-		atom.Base,
-		atom.Br,
-		atom.Embed,
-		atom.Hr,
-		atom.Iframe,
-		atom.Img,
-		atom.Input,
-		atom.Link,
-		atom.Meta,
-		atom.Param,
-		atom.Source,
-		atom.Track:
-		return true
-	}
-	return false
-}
@@ -0,0 +1,116 @@
+package boilerpipe
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// cancelReader cancels a context when the second part of a document is read,
+// like a slow document whose deadline is exceeded while it's parsed.
+type cancelReader struct {
+	parts  []string
+	read   int
+	cancel context.CancelFunc
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	if len(r.parts) == 0 {
+		return 0, io.EOF
+	}
+	r.read++
+	if r.read == 2 {
+		r.cancel()
+	}
+	n := copy(p, r.parts[0])
+	r.parts[0] = r.parts[0][n:]
+	if r.parts[0] == "" {
+		r.parts = r.parts[1:]
+	}
+	return n, nil
+}
+
+func TestParseDocumentContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := &cancelReader{
+		parts: []string{
+			`<html><head><title>The title</title></head><body><p>The first paragraph.</p>`,
+			`<p>The second paragraph.</p></body></html>`,
+		},
+		cancel: cancel,
+	}
+
+	doc, err := ParseDocumentContext(ctx, r, nil)
+	if doc == nil {
+		t.Fatal("expected partial document")
+	}
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled error but got %v", err)
+	}
+	if exp := "The title"; doc.Title != exp {
+		t.Errorf("expected title '%s' but got '%s'", exp, doc.Title)
+	}
+	if text := doc.Text(true, true); strings.Contains(text, "second") {
+		t.Errorf("expected parsing to stop but got '%s'", text)
+	}
+}
+
+// cancelFilter cancels a context when it processes a document.
+type cancelFilter struct {
+	cancel    context.CancelFunc
+	processed *int
+}
+
+func (cancelFilter) Name() string { return "Cancel" }
+
+func (f cancelFilter) Process(doc *Document) bool {
+	*f.processed++
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return true
+}
+
+func TestPipelineProcessContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var processed int
+	pipeline := &Pipeline{
+		PipelineName: "Test",
+		Filters: []Filter{
+			cancelFilter{processed: &processed},
+			&Pipeline{
+				PipelineName: "Nested",
+				Filters: []Filter{
+					cancelFilter{cancel: cancel, processed: &processed},
+					cancelFilter{processed: &processed},
+				},
+			},
+			cancelFilter{processed: &processed},
+		},
+	}
+
+	hasChanged, err := pipeline.ProcessContext(ctx, &Document{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled error but got %v", err)
+	}
+	if !hasChanged {
+		t.Error("expected document to be changed")
+	}
+	if processed != 2 {
+		t.Errorf("expected 2 filters to be processed but got %d", processed)
+	}
+
+	processed = 0
+	if _, err := pipeline.ProcessContext(context.Background(), &Document{}); err != nil {
+		t.Fatal(err)
+	}
+	if processed != 4 {
+		t.Errorf("expected 4 filters to be processed but got %d", processed)
+	}
+}
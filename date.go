@@ -0,0 +1,472 @@
+package boilerpipe
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+	"golang.org/x/net/html"
+)
+
+// DateKind is the kind of date a candidate is.
+type DateKind int
+
+const (
+	DateKindPublished DateKind = iota
+	DateKindModified
+)
+
+func (k DateKind) String() string {
+	switch k {
+	case DateKindPublished:
+		return "published"
+	case DateKindModified:
+		return "modified"
+	}
+	return "DateKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// MarshalText encodes the kind as its name.
+func (k DateKind) MarshalText() ([]byte, error) {
+	if k != DateKindPublished && k != DateKindModified {
+		return nil, fmt.Errorf("boilerpipe: invalid date kind %d", int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a kind from its name.
+func (k *DateKind) UnmarshalText(text []byte) error {
+	for _, kind := range []DateKind{DateKindPublished, DateKindModified} {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("boilerpipe: unknown date kind %q", text)
+}
+
+// DateSource is where a date candidate was found. Sources are ordered from
+// the most to the least reliable.
+type DateSource int
+
+const (
+	DateSourceLinkedData DateSource = iota
+	DateSourceMicrodata
+	DateSourceMeta
+	DateSourceTime
+	DateSourceURL
+	DateSourceText
+)
+
+var dateSourceNames = [...]string{
+	DateSourceLinkedData: "linked-data",
+	DateSourceMicrodata:  "microdata",
+	DateSourceMeta:       "meta",
+	DateSourceTime:       "time",
+	DateSourceURL:        "url",
+	DateSourceText:       "text",
+}
+
+func (s DateSource) String() string {
+	if s < 0 || int(s) >= len(dateSourceNames) {
+		return "DateSource(" + strconv.Itoa(int(s)) + ")"
+	}
+	return dateSourceNames[s]
+}
+
+// MarshalText encodes the source as its name.
+func (s DateSource) MarshalText() ([]byte, error) {
+	if s < 0 || int(s) >= len(dateSourceNames) {
+		return nil, fmt.Errorf("boilerpipe: invalid date source %d", int(s))
+	}
+	return []byte(dateSourceNames[s]), nil
+}
+
+// UnmarshalText decodes a source from its name.
+func (s *DateSource) UnmarshalText(text []byte) error {
+	for i, name := range dateSourceNames {
+		if name == string(text) {
+			*s = DateSource(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("boilerpipe: unknown date source %q", text)
+}
+
+// A DateCandidate is a date found in a document along with where it was
+// found.
+type DateCandidate struct {
+	Time   time.Time  `json:"time"`
+	Kind   DateKind   `json:"kind"`
+	Source DateSource `json:"source"`
+
+	// Value is the raw value the date was parsed from.
+	Value string `json:"value"`
+}
+
+// metaDatePublishedKeys are the <meta> names and properties of published
+// dates, in order of preference.
+var metaDatePublishedKeys = []string{
+	"article:published_time",
+	"og:published_time",
+	"datepublished",
+	"pubdate",
+	"publishdate",
+	"publish-date",
+	"article.published",
+	"parsely-pub-date",
+	"sailthru.date",
+	"dc.date.issued",
+	"dcterms.issued",
+	"dcterms.created",
+	"dc.date",
+	"date",
+}
+
+// metaDateModifiedKeys are the <meta> names and properties of modified dates,
+// in order of preference.
+var metaDateModifiedKeys = []string{
+	"article:modified_time",
+	"og:updated_time",
+	"datemodified",
+	"lastmod",
+	"last-modified",
+	"article.updated",
+	"dcterms.modified",
+	"dc.date.modified",
+}
+
+// TimeElement collects the datetime of a <time> element as a date candidate.
+// The date is considered a modified date if its itemprop or class says so.
+func (h *contentHandler) TimeElement(tok *html.Token) {
+	var (
+		value  string
+		exists bool
+		kind   = DateKindPublished
+	)
+
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "datetime":
+			value = attr.Val
+			exists = true
+		case "itemprop", "class":
+			v := strings.ToLower(attr.Val)
+			if strings.Contains(v, "modified") || strings.Contains(v, "updated") {
+				kind = DateKindModified
+			}
+		}
+	}
+
+	if !exists {
+		return
+	}
+
+	t, ok := ParseDate(value)
+	if !ok {
+		return
+	}
+
+	h.dates = append(h.dates, DateCandidate{
+		Time:   t,
+		Kind:   kind,
+		Source: DateSourceTime,
+		Value:  value,
+	})
+}
+
+// metaDateCandidates returns the date candidates found in <meta> tags.
+func metaDateCandidates(m *Metadata) (candidates []DateCandidate) {
+	add := func(kind DateKind, keys []string) {
+		for _, key := range keys {
+			for _, v := range m.Properties[key] {
+				t, ok := ParseDate(v)
+				if !ok {
+					continue
+				}
+				candidates = append(candidates, DateCandidate{
+					Time:   t,
+					Kind:   kind,
+					Source: DateSourceMeta,
+					Value:  v,
+				})
+			}
+		}
+	}
+	add(DateKindPublished, metaDatePublishedKeys)
+	add(DateKindModified, metaDateModifiedKeys)
+	return
+}
+
+// schemaDateCandidates returns the date candidates of a schema.org article.
+func schemaDateCandidates(a *SchemaArticle, source DateSource) (candidates []DateCandidate) {
+	if a == nil {
+		return
+	}
+	if !a.DatePublished.IsZero() {
+		candidates = append(candidates, DateCandidate{
+			Time:   a.DatePublished,
+			Kind:   DateKindPublished,
+			Source: source,
+		})
+	}
+	if !a.DateModified.IsZero() {
+		candidates = append(candidates, DateCandidate{
+			Time:   a.DateModified,
+			Kind:   DateKindModified,
+			Source: source,
+		})
+	}
+	return
+}
+
+// maxTextDateWords is the maximum number of words a text block can have to be
+// searched for dates, since dates are usually in short bylines.
+const maxTextDateWords = 20
+
+// maxTextDateCandidates is the maximum number of date candidates found in
+// text blocks.
+const maxTextDateCandidates = 10
+
+var (
+	reTextDateMonthFirst = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+	reTextDateDayFirst   = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?,?\s+(\d{4})\b`)
+	reTextDateNumeric    = regexp.MustCompile(`\b(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})\b`)
+	reTextDateCJK        = regexp.MustCompile(`(\d{4})\s*年\s*(\d{1,2})\s*月\s*(\d{1,2})\s*日`)
+)
+
+var textDateMonths = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March,
+	"apr": time.April, "may": time.May, "jun": time.June,
+	"jul": time.July, "aug": time.August, "sep": time.September,
+	"sept": time.September, "oct": time.October, "nov": time.November,
+	"dec": time.December,
+}
+
+// textDates returns the dates found in visible text.
+func textDates(text string) (dates []time.Time) {
+	add := func(year, month, day string) {
+		y, _ := strconv.Atoi(year)
+		d, _ := strconv.Atoi(day)
+
+		m, exists := textDateMonths[strings.ToLower(month)]
+		if !exists {
+			n, err := strconv.Atoi(month)
+			if err != nil {
+				return
+			}
+			m = time.Month(n)
+		}
+
+		if t, ok := validDate(y, m, d); ok {
+			dates = append(dates, t)
+		}
+	}
+
+	for _, ss := range reTextDateMonthFirst.FindAllStringSubmatch(text, -1) {
+		add(ss[3], ss[1], ss[2])
+	}
+	for _, ss := range reTextDateDayFirst.FindAllStringSubmatch(text, -1) {
+		add(ss[3], ss[2], ss[1])
+	}
+	for _, ss := range reTextDateNumeric.FindAllStringSubmatch(text, -1) {
+		add(ss[1], ss[2], ss[3])
+	}
+	for _, ss := range reTextDateCJK.FindAllStringSubmatch(text, -1) {
+		add(ss[1], ss[2], ss[3])
+	}
+	return
+}
+
+// validDate returns the date at midnight UTC, or false if there is no such
+// date, such as February 31, which time.Date would normalize to March.
+func validDate(y int, m time.Month, d int) (t time.Time, ok bool) {
+	if m < time.January || m > time.December || d < 1 || d > 31 {
+		return
+	}
+	t = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if t.Month() != m || t.Day() != d {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// textDateCandidates returns the date candidates found in short text blocks.
+func textDateCandidates(textBlocks []*TextBlock) (candidates []DateCandidate) {
+	for _, tb := range textBlocks {
+		if tb.NumWords > maxTextDateWords || tb.HasLabel(LabelComment) {
+			continue
+		}
+		for _, t := range textDates(tb.Text) {
+			candidates = append(candidates, DateCandidate{
+				Time:   t,
+				Kind:   DateKindPublished,
+				Source: DateSourceText,
+				Value:  tb.Text,
+			})
+			if len(candidates) == maxTextDateCandidates {
+				return
+			}
+		}
+	}
+	return
+}
+
+// SetURL adds the date found in the document's URL, if any, as a date
+// candidate, and resolves the document dates, relative URLs and clean title
+// again.
+func (doc *Document) SetURL(u *normurl.URL) {
+	doc.URL = u
+	doc.resolveURLs()
+	doc.CleanTitle = doc.cleanTitle()
+
+	candidates := doc.DateCandidates[:0:0]
+	for _, c := range doc.DateCandidates {
+		if c.Source != DateSourceURL {
+			candidates = append(candidates, c)
+		}
+	}
+
+	if u != nil {
+		if t, exists := u.Date(); exists {
+			candidates = append(candidates, DateCandidate{
+				Time:   t,
+				Kind:   DateKindPublished,
+				Source: DateSourceURL,
+				Value:  u.String(),
+			})
+		}
+	}
+
+	doc.DateCandidates = candidates
+	doc.resolveDates()
+}
+
+// resolveDates sets the published and modified dates of the document from
+// its date candidates. For each kind the candidate from the most reliable
+// source is chosen, and the first one found if a source has several. A
+// modified date is ignored if it's before the published date. Dates found in
+// the text are only candidates, since they may be the dates of anything the
+// text mentions.
+func (doc *Document) resolveDates() {
+	doc.Date = bestDate(doc.DateCandidates, DateKindPublished, time.Time{})
+	doc.DateModified = bestDate(doc.DateCandidates, DateKindModified, doc.Date)
+}
+
+func bestDate(candidates []DateCandidate, kind DateKind, notBefore time.Time) (t time.Time) {
+	var best *DateCandidate
+	for i := range candidates {
+		c := &candidates[i]
+		if c.Kind != kind || c.Source == DateSourceText || c.Time.IsZero() || c.Time.Before(notBefore) {
+			continue
+		}
+		if best == nil || c.Source < best.Source {
+			best = c
+		}
+	}
+	if best != nil {
+		t = best.Time
+	}
+	return
+}
+
+// dateLayouts are the layouts tried by ParseDate, in order. Layouts without
+// a timezone are parsed as UTC.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"2006.01.02",
+	"20060102",
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006 3:04 pm",
+	"January 2, 2006 15:04",
+	"Jan 2, 2006 3:04 PM",
+	"Jan 2, 2006 15:04",
+	"Monday, January 2, 2006 3:04 PM",
+	"2 January 2006 15:04",
+	"2 Jan 2006 15:04",
+	"2006年1月2日 15:04:05",
+	"2006年1月2日 15:04",
+	"2006年1月2日15:04",
+}
+
+var (
+	reUnixTimestamp = regexp.MustCompile(`^\d{9,10}(\d{3})?$`)
+	reNumericDate   = regexp.MustCompile(`^(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{4})$`)
+)
+
+// ParseDate parses a date leniently, accepting RFC3339 and other ISO 8601
+// variants with or without a timezone, date-only values, RFC1123 and the
+// other common Internet formats, unix timestamps in seconds or milliseconds,
+// and common localized formats such as "January 2, 2006" or "2006年1月2日".
+// Dates without a timezone are returned in UTC, and dates which don't exist,
+// such as February 31, aren't parsed.
+func ParseDate(s string) (t time.Time, ok bool) {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+
+	if reUnixTimestamp.MatchString(s) {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err == nil {
+			if len(s) > 10 {
+				return time.Unix(0, n*int64(time.Millisecond)).UTC(), true
+			}
+			return time.Unix(n, 0).UTC(), true
+		}
+	}
+
+	// Numeric dates such as 02/01/2006 are read as day first when the
+	// first number can't be a month, else month first.
+	if ss := reNumericDate.FindStringSubmatch(s); ss != nil {
+		a, _ := strconv.Atoi(ss[1])
+		b, _ := strconv.Atoi(ss[2])
+		y, _ := strconv.Atoi(ss[3])
+		m, d := a, b
+		if a > 12 || ss[0][len(ss[1])] == '.' {
+			m, d = b, a
+		}
+		return validDate(y, time.Month(m), d)
+	}
+
+	// Fallback to the first date in the text, e.g. "Monday, May 22nd 2017"
+	if dates := textDates(s); len(dates) > 0 {
+		return dates[0], true
+	}
+
+	return
+}
@@ -0,0 +1,205 @@
+package boilerpipe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+)
+
+var textDatesTestData = map[string]string{
+	"Published Thursday, April 20, 2017 | 2:02 p.m.": "2017-04-20",
+	"Monday, May 22nd 2017":                          "2017-05-22",
+	"22 May 2017":                                    "2017-05-22",
+	"Updated 2019-03-28 08:58":                       "2019-03-28",
+	"Updated 2020-02-29 08:58":                       "2020-02-29",
+	"2019年03月30日08:37  来源：人民网":                       "2019-03-30",
+}
+
+func TestTextDates(t *testing.T) {
+	for text, exp := range textDatesTestData {
+		dates := textDates(text)
+		if len(dates) != 1 {
+			t.Errorf("%s: expected 1 date but got %d", text, len(dates))
+			continue
+		}
+		if act := dates[0].Format("2006-01-02"); act != exp {
+			t.Errorf("%s: expected date '%s' but got '%s'", text, exp, act)
+		}
+	}
+}
+
+func TestTextDatesInvalid(t *testing.T) {
+	for _, text := range []string{
+		"Posted February 31, 2020",
+		"31 Feb 2020",
+		"Updated 2019-02-29 08:58",
+		"2019年04月31日08:37",
+		"June 0, 2019",
+	} {
+		if dates := textDates(text); len(dates) != 0 {
+			t.Errorf("%s: expected no dates but got %v", text, dates)
+		}
+	}
+}
+
+const dateCandidatesTestHTML = `<html>
+<head>
+<meta property="article:published_time" content="2017-05-22T02:00:00Z">
+<meta property="article:modified_time" content="2017-05-21T02:00:00Z">
+<meta property="og:updated_time" content="2017-05-23T02:00:00Z">
+</head>
+<body>
+<p>By Jane Doe, May 20, 2017</p>
+<time datetime="2017-05-19T02:00:00Z">May 19, 2017</time>
+<time class="updated" datetime="2017-05-24T02:00:00Z">May 24, 2017</time>
+</body>
+</html>`
+
+func TestDateCandidates(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(dateCandidatesTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := make(map[DateSource]int)
+	for _, c := range doc.DateCandidates {
+		count[c.Source]++
+	}
+	if count[DateSourceMeta] != 3 || count[DateSourceTime] != 2 || count[DateSourceText] != 3 {
+		t.Errorf("unexpected date candidates %v", doc.DateCandidates)
+	}
+
+	// Meta tags are preferred over <time> elements and text
+	expDate := time.Date(2017, time.May, 22, 2, 0, 0, 0, time.UTC)
+	if !doc.Date.Equal(expDate) {
+		t.Errorf("expected date '%s' but got '%s'", expDate, doc.Date)
+	}
+
+	// Modified dates before the published date are ignored
+	expDate = time.Date(2017, time.May, 23, 2, 0, 0, 0, time.UTC)
+	if !doc.DateModified.Equal(expDate) {
+		t.Errorf("expected date modified '%s' but got '%s'", expDate, doc.DateModified)
+	}
+}
+
+func TestDateCandidatesText(t *testing.T) {
+	tests := []struct {
+		file    string
+		exp     string
+		expDate string
+	}{
+		{"5.html", "2019-03-28", ""},
+		{"6.html", "2019-03-30", "2019-03-30"},
+	}
+
+	for _, test := range tests {
+		f, err := os.Open(filepath.Join("testdata", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := ParseDocument(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := false
+		for _, c := range doc.DateCandidates {
+			if c.Source == DateSourceText && c.Time.Format("2006-01-02") == test.exp {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: expected text date candidate '%s' but got %v", test.file, test.exp, doc.DateCandidates)
+		}
+
+		// Dates in the text don't resolve the date of the document
+		var act string
+		if !doc.Date.IsZero() {
+			act = doc.Date.Format("2006-01-02")
+		}
+		if act != test.expDate {
+			t.Errorf("%s: expected date '%s' but got '%s'", test.file, test.expDate, act)
+		}
+	}
+}
+
+func TestSetURL(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(`<html><body><p>Hello world.</p></body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Date.IsZero() {
+		t.Fatalf("expected no date but got '%s'", doc.Date)
+	}
+
+	u, err := normurl.Parse("https://lasvegassun.com/news/2017/may/22/yucca-mountain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetURL(u)
+
+	expDate := time.Date(2017, time.May, 22, 0, 0, 0, 0, time.UTC)
+	if !doc.Date.Equal(expDate) {
+		t.Errorf("expected date '%s' but got '%s'", expDate, doc.Date)
+	}
+
+	// Setting the URL again replaces the URL candidate
+	doc.SetURL(u)
+	if l := len(doc.DateCandidates); l != 1 {
+		t.Errorf("expected 1 date candidate but got %d", l)
+	}
+	if c := doc.DateCandidates[0]; c.Source != DateSourceURL || c.Source.String() != "url" {
+		t.Errorf("unexpected date candidate %+v", c)
+	}
+}
+
+var parseDateTestData = map[string]string{
+	"2019-03-28T08:58:14+08:00":       "2019-03-28T00:58:14Z",
+	"2019-03-28T08:58:14.123+0800":    "2019-03-28T00:58:14.123Z",
+	"2017-04-20T14:02":                "2017-04-20T14:02:00Z",
+	"2017-04-20 14:02:03":             "2017-04-20T14:02:03Z",
+	"2017-04-20":                      "2017-04-20T00:00:00Z",
+	"2017/04/20":                      "2017-04-20T00:00:00Z",
+	"Thu, 20 Apr 2017 14:02:03 GMT":   "2017-04-20T14:02:03Z",
+	"Thu, 20 Apr 2017 14:02:03 -0700": "2017-04-20T21:02:03Z",
+	"1158061378":                      "2006-09-12T11:42:58Z",
+	"1158061378000":                   "2006-09-12T11:42:58Z",
+	"April 20, 2017":                  "2017-04-20T00:00:00Z",
+	"April 20, 2017 2:02 PM":          "2017-04-20T14:02:00Z",
+	"20 April 2017":                   "2017-04-20T00:00:00Z",
+	"Thursday, April 20th, 2017":      "2017-04-20T00:00:00Z",
+	"04/20/2017":                      "2017-04-20T00:00:00Z",
+	"20/04/2017":                      "2017-04-20T00:00:00Z",
+	"20.04.2017":                      "2017-04-20T00:00:00Z",
+	"2017年4月20日 14:02":                "2017-04-20T14:02:00Z",
+	"2017年04月20日":                     "2017-04-20T00:00:00Z",
+}
+
+func TestParseDate(t *testing.T) {
+	for s, exp := range parseDateTestData {
+		expTime, err := time.Parse(time.RFC3339Nano, exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actTime, ok := ParseDate(s)
+		if !ok {
+			t.Errorf("%s: expected date to parse", s)
+			continue
+		}
+		if !expTime.Equal(actTime) {
+			t.Errorf("%s: expected date '%s' but got '%s'", s, expTime, actTime)
+		}
+	}
+
+	for _, s := range []string{"", "yesterday", "13/13/2017", "12345", "31/02/2020", "2020-02-31", "Feb 31, 2020", "29.02.2019"} {
+		if actTime, ok := ParseDate(s); ok {
+			t.Errorf("%s: expected date not to parse but got '%s'", s, actTime)
+		}
+	}
+}
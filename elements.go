@@ -0,0 +1,338 @@
+package boilerpipe
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// The tokenizer returns the tags as they are written, so the content handler
+// implements the parts of the HTML tree construction rules which keep its
+// element stack in sync with the elements a browser would have open: void
+// elements, end tags which are implied by a start tag, end tags which don't
+// match an open element and elements which are left open at the end of the
+// document.
+
+// isVoidElement returns true if the element can't have any content, and so
+// has no end tag.
+func isVoidElement(a atom.Atom) bool {
+	switch a {
+	case atom.Area,
+		atom.Base,
+		atom.Br,
+		atom.Col,
+		atom.Embed,
+		atom.Hr,
+		atom.Img,
+		atom.Input,
+		atom.Link,
+		atom.Meta,
+		atom.Param,
+		atom.Source,
+		atom.Track,
+		atom.Wbr:
+		return true
+	}
+	return false
+}
+
+// isHeadElement returns true if the element may be in the <head> of the
+// document.
+func isHeadElement(a atom.Atom) bool {
+	switch a {
+	case atom.Html,
+		atom.Head,
+		atom.Title,
+		atom.Meta,
+		atom.Link,
+		atom.Base,
+		atom.Script,
+		atom.Style,
+		atom.Noscript,
+		atom.Template:
+		return true
+	}
+	return false
+}
+
+// closesParagraph returns true if the start tag of the element closes an open
+// <p> element.
+func closesParagraph(a atom.Atom) bool {
+	switch a {
+	case atom.Address,
+		atom.Article,
+		atom.Aside,
+		atom.Blockquote,
+		atom.Center,
+		atom.Details,
+		atom.Dialog,
+		atom.Dir,
+		atom.Div,
+		atom.Dl,
+		atom.Dd,
+		atom.Dt,
+		atom.Fieldset,
+		atom.Figcaption,
+		atom.Figure,
+		atom.Footer,
+		atom.Form,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Header,
+		atom.Hgroup,
+		atom.Hr,
+		atom.Li,
+		atom.Main,
+		atom.Menu,
+		atom.Nav,
+		atom.Ol,
+		atom.P,
+		atom.Pre,
+		atom.Section,
+		atom.Summary,
+		atom.Table,
+		atom.Ul:
+		return true
+	}
+	return false
+}
+
+func isHeading(a atom.Atom) bool {
+	switch a {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return true
+	}
+	return false
+}
+
+// isScopeBoundary returns true if the element ends the scope in which open
+// elements are searched for. Elements outside of a table cell can't be closed
+// from within it, for example.
+func isScopeBoundary(a atom.Atom) bool {
+	switch a {
+	case atom.Applet,
+		atom.Caption,
+		atom.Html,
+		atom.Table,
+		atom.Td,
+		atom.Th,
+		atom.Marquee,
+		atom.Object,
+		atom.Template:
+		return true
+	}
+	return false
+}
+
+// isTableElement returns true if the element is part of the structure of a
+// table, whose elements are searched for within the table.
+func isTableElement(a atom.Atom) bool {
+	switch a {
+	case atom.Table,
+		atom.Caption,
+		atom.Colgroup,
+		atom.Thead,
+		atom.Tbody,
+		atom.Tfoot,
+		atom.Tr,
+		atom.Td,
+		atom.Th:
+		return true
+	}
+	return false
+}
+
+func (stack *atomStack) matches(i int, tok *html.Token) bool {
+	if stack.s[i] != tok.DataAtom {
+		return false
+	}
+	// Elements without an atom, such as custom elements, are matched by name
+	return tok.DataAtom != 0 || stack.names[i] == tok.Data
+}
+
+// isOpen returns true if any of the elements are open.
+func (stack *atomStack) isOpen(atoms ...atom.Atom) bool {
+	for _, a := range atoms {
+		if stack.counts[a.String()] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// lastIndexInScope returns the index of the innermost open element matching
+// any of the atoms, or -1 if there is none in scope. The scope also ends at
+// the boundary atoms.
+func (stack *atomStack) lastIndexInScope(atoms []atom.Atom, boundaries ...atom.Atom) int {
+	return stack.lastIndex(atoms, isScopeBoundary, boundaries)
+}
+
+// lastIndexInTable is like lastIndexInScope, but only ends the scope at the
+// table which the elements are part of.
+func (stack *atomStack) lastIndexInTable(atoms []atom.Atom, boundaries ...atom.Atom) int {
+	return stack.lastIndex(atoms, isTableBoundary, boundaries)
+}
+
+func isTableBoundary(a atom.Atom) bool {
+	return a == atom.Table || a == atom.Html || a == atom.Template
+}
+
+func (stack *atomStack) lastIndex(atoms []atom.Atom, isBoundary func(atom.Atom) bool, boundaries []atom.Atom) int {
+	if !stack.isOpen(atoms...) {
+		return -1
+	}
+	for i := len(stack.s) - 1; i >= 0; i-- {
+		a := stack.s[i]
+		for _, b := range atoms {
+			if a == b {
+				return i
+			}
+		}
+		if isBoundary(a) {
+			return -1
+		}
+		for _, b := range boundaries {
+			if a == b {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// endTagIndex returns the index of the open element closed by the end tag, or
+// -1 if it doesn't match any open element in scope.
+func (stack *atomStack) endTagIndex(tok *html.Token) int {
+	if stack.counts[tok.Data] == 0 && !(isHeading(tok.DataAtom) && stack.isOpen(atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6)) {
+		return -1
+	}
+	for i := len(stack.s) - 1; i >= 0; i-- {
+		if stack.matches(i, tok) {
+			return i
+		}
+
+		// The end tag of any heading ends the open heading
+		a := stack.s[i]
+		if isHeading(tok.DataAtom) && isHeading(a) {
+			return i
+		}
+
+		if isTableElement(tok.DataAtom) {
+			// Table elements are searched for within the table
+			if isTableBoundary(a) {
+				return -1
+			}
+			continue
+		}
+
+		if isScopeBoundary(a) ||
+			tok.DataAtom == atom.Li && (a == atom.Ul || a == atom.Ol) ||
+			tok.DataAtom == atom.P && a == atom.Button {
+			return -1
+		}
+	}
+	return -1
+}
+
+// closeElements ends the open elements until n elements are left open.
+func (h *contentHandler) closeElements(n int) {
+	for len(h.atomStack.s) > n {
+		i := len(h.atomStack.s) - 1
+		h.inLinkedDataJSON = false
+		h.EndElement(&html.Token{
+			Type:     html.EndTagToken,
+			DataAtom: h.atomStack.s[i],
+			Data:     h.atomStack.names[i],
+		})
+	}
+}
+
+// closeElementsThrough ends the open element at index i and the elements
+// within it, unless i is -1.
+func (h *contentHandler) closeElementsThrough(i int) {
+	if i != -1 {
+		h.closeElements(i)
+	}
+}
+
+// beforeStartTag ends the open elements whose end tags are implied by the
+// start tag, such as an open <li> by the start of the next <li>, and starts
+// the <body> if the start tag implies it. It returns false if the start tag
+// should be ignored, such as a second <body>.
+func (h *contentHandler) beforeStartTag(tok *html.Token) bool {
+	stack := h.atomStack
+	a := tok.DataAtom
+
+	switch a {
+	case atom.Html:
+		return len(stack.s) == 0
+	case atom.Head, atom.Body:
+		if h.bodyStarted {
+			return false
+		}
+	}
+
+	if !h.bodyStarted && !isHeadElement(a) && a != atom.Body {
+		h.startBody()
+	}
+
+	if closesParagraph(a) {
+		h.closeElementsThrough(stack.lastIndexInScope([]atom.Atom{atom.P}, atom.Button))
+	}
+
+	switch a {
+	case atom.Li:
+		h.closeElementsThrough(stack.lastIndexInScope([]atom.Atom{atom.Li}, atom.Ul, atom.Ol, atom.Menu))
+	case atom.Dt, atom.Dd:
+		h.closeElementsThrough(stack.lastIndexInScope([]atom.Atom{atom.Dt, atom.Dd}, atom.Dl))
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		if n := len(stack.s); n > 0 && isHeading(stack.s[n-1]) {
+			h.closeElements(n - 1)
+		}
+	case atom.A:
+		h.closeElementsThrough(stack.lastIndexInScope([]atom.Atom{atom.A}))
+	case atom.Option:
+		if n := len(stack.s); n > 0 && stack.s[n-1] == atom.Option {
+			h.closeElements(n - 1)
+		}
+	case atom.Optgroup:
+		h.closeElementsThrough(stack.lastIndexInScope([]atom.Atom{atom.Option, atom.Optgroup}, atom.Select))
+	case atom.Caption, atom.Colgroup, atom.Thead, atom.Tbody, atom.Tfoot:
+		if i := stack.lastIndexInTable([]atom.Atom{atom.Table}); i != -1 {
+			h.closeElements(i + 1)
+		}
+	case atom.Tr:
+		h.closeElementsThrough(stack.lastIndexInTable([]atom.Atom{atom.Tr}))
+	case atom.Td, atom.Th:
+		h.closeElementsThrough(stack.lastIndexInTable([]atom.Atom{atom.Td, atom.Th}, atom.Tr))
+	}
+
+	return true
+}
+
+// beforeText starts the <body> if the text implies it, which is text outside
+// of the elements of the <head>.
+func (h *contentHandler) beforeText(tok *html.Token) {
+	if h.bodyStarted || strings.TrimSpace(tok.Data) == "" {
+		return
+	}
+	if n := len(h.atomStack.s); n > 0 {
+		if a := h.atomStack.s[n-1]; a != atom.Html && a != atom.Head {
+			return
+		}
+	}
+	h.startBody()
+}
+
+// startBody starts the <body> of a document which doesn't start it, ending
+// the <head> if it's open.
+func (h *contentHandler) startBody() {
+	if i := h.atomStack.lastIndexInScope([]atom.Atom{atom.Head}); i != -1 {
+		h.closeElements(i)
+	}
+	h.StartElement(&html.Token{
+		Type:     html.StartTagToken,
+		DataAtom: atom.Body,
+		Data:     atom.Body.String(),
+	})
+}
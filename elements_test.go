@@ -0,0 +1,159 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func parseTestHandler(t testing.TB, data []byte) *contentHandler {
+	h, err := parse(context.Background(), bytes.NewReader(data), nil, func(tok *html.Token, h *contentHandler) {
+		h.TextToken(tok)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// checkHandlerState checks that the content handler has ended all elements
+// at the end of the document.
+func checkHandlerState(t testing.TB, h *contentHandler) {
+	t.Helper()
+	if l := len(h.atomStack.s); l != 0 {
+		t.Errorf("expected no open elements but got %d", l)
+	}
+	for _, c := range []struct {
+		name  string
+		depth int
+	}{
+		{"tag", h.depthTag},
+		{"ignoreable", h.depthIgnoreable},
+		{"anchor", h.depthAnchor},
+		{"body", h.depthBody},
+	} {
+		if c.depth != 0 {
+			t.Errorf("expected %s depth 0 but got %d", c.name, c.depth)
+		}
+	}
+}
+
+func TestMalformedHTML(t *testing.T) {
+	tests := []struct {
+		html string
+		exp  []string
+	}{
+		{`<p>Before</p><script>var x = "<p>";`, []string{"Before"}},
+		{`<ul><li>One<li>Two<li>Three</ul><p>After</p>`, []string{"One", "Two", "Three", "After"}},
+		{`<p><b>Bold<i>both</b>italic</i></p><p>Next</p>`, []string{"Boldbothitalic", "Next"}},
+		{`</div></span><p>Text</p></p></br><div>More</div>`, []string{"Text", "More"}},
+		{`<title>The title</title><p>Text`, []string{"Text"}},
+		{`<html><body><p>One</p></body></html><p>Two</p>`, []string{"One", "Two"}},
+		{`<p><a href="/1">One<a href="/2">Two</a> plain words</p>`, []string{"One Two plain words"}},
+		{`<table><tr><td>A<td>B<tr><td>C</table><p>After`, []string{"A", "B", "C", "After"}},
+		{`<div><iframe src="/embed">Fallback</iframe><p>Text</p></div>`, []string{"Text"}},
+		{`<h2>Heading</h3><p>Text</p>`, []string{"Heading", "Text"}},
+		{`<dl><dt>Term<dd>Definition<dt>Other</dl>`, []string{"Term", "Definition", "Other"}},
+		{`<div/><p>Text</p>`, []string{"Text"}},
+	}
+
+	for _, test := range tests {
+		h := parseTestHandler(t, []byte(test.html))
+		checkHandlerState(t, h)
+
+		doc := newDocument(h)
+		var act []string
+		for _, tb := range doc.TextBlocks {
+			act = append(act, tb.Text)
+		}
+		if strings.Join(act, "\n") != strings.Join(test.exp, "\n") {
+			t.Errorf("%s: expected text blocks %q but got %q", test.html, test.exp, act)
+		}
+	}
+}
+
+func TestMalformedHTMLTagLevel(t *testing.T) {
+	// The unclosed list items don't increase the tag level of the paragraph
+	// after the list
+	doc, err := ParseDocument(strings.NewReader(`<body><div><p>First</p><ul><li>One<li>Two</ul><p>Second</p></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := len(doc.TextBlocks); l != 4 {
+		t.Fatalf("expected 4 text blocks but got %d", l)
+	}
+	if first, second := doc.TextBlocks[0], doc.TextBlocks[3]; first.TagLevel != second.TagLevel {
+		t.Errorf("expected tag level %d but got %d", first.TagLevel, second.TagLevel)
+	}
+	if tb := doc.TextBlocks[3]; tb.HasLabel(LabelList) {
+		t.Errorf("expected '%s' not to be a list item", tb.Text)
+	}
+}
+
+func TestMalformedHTMLLabels(t *testing.T) {
+	tests := []struct {
+		html string
+		exp  [][]Label
+	}{
+		{`<div><h1>The <b>title</b></h1>Text after</div>`, [][]Label{{LabelHeading1, LabelHeading}, {}}},
+		{`<div><h1><img src="/a.png"></h1>Text after</div>`, [][]Label{{}}},
+		{`<ul><li><h2>Item <em>title</em></h2>Text after</li></ul>`, [][]Label{{LabelHeading2, LabelHeading, LabelList}, {}}},
+	}
+
+	for _, test := range tests {
+		doc := newDocument(parseTestHandler(t, []byte(test.html)))
+		if len(doc.TextBlocks) != len(test.exp) {
+			t.Fatalf("%s: expected %d text blocks but got %d", test.html, len(test.exp), len(doc.TextBlocks))
+		}
+		for i, tb := range doc.TextBlocks {
+			ok := len(tb.Labels()) == len(test.exp[i])
+			for _, label := range test.exp[i] {
+				ok = ok && tb.HasLabel(label)
+			}
+			if !ok {
+				t.Errorf("%s: expected '%s' to have labels %v but got %v", test.html, tb.Text, test.exp[i], tb.Labels())
+			}
+		}
+	}
+}
+
+func TestSelfClosingTag(t *testing.T) {
+	tests := []struct {
+		html string
+		exp  int
+	}{
+		// Elements which aren't void are left open
+		{`<div/><p>Text</p>`, 3},
+		{`<p>Text<br/></p>`, 2},
+		// Elements of foreign content are ended
+		{`<svg><path d="M0 0"/></svg><p>Text</p>`, 2},
+	}
+
+	for _, test := range tests {
+		h := parseTestHandler(t, []byte(test.html))
+		checkHandlerState(t, h)
+
+		doc := newDocument(h)
+		if len(doc.TextBlocks) != 1 {
+			t.Fatalf("%s: expected 1 text block but got %d", test.html, len(doc.TextBlocks))
+		}
+		if act := doc.TextBlocks[0].TagLevel; act != test.exp {
+			t.Errorf("%s: expected tag level %d but got %d", test.html, test.exp, act)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add([]byte(`<html><head><title>Title</title></head><body><p>Text</p></body></html>`))
+	f.Add([]byte(`<ul><li>One<li>Two</ul>`))
+	f.Add([]byte(`<script>unclosed`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h := parseTestHandler(t, data)
+		checkHandlerState(t, h)
+		newDocument(h)
+	})
+}
@@ -0,0 +1,77 @@
+package boilerpipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// ErrRead is the kind of a ParseError which occurred reading the
+	// document, such as a truncated network read.
+	ErrRead = errors.New("boilerpipe: error reading document")
+
+	// ErrLimitExceeded is the kind of a ParseError which occurred because the
+	// document exceeded a size limit, such as html.ErrBufferExceeded.
+	ErrLimitExceeded = errors.New("boilerpipe: document exceeds limit")
+
+	// ErrCanceled is the kind of a ParseError which occurred because the
+	// context of the parse was canceled or its deadline exceeded.
+	ErrCanceled = errors.New("boilerpipe: parsing canceled")
+)
+
+// A ParseError is returned along with the partial Document when parsing a
+// document stops before its end. It matches both its kind and the underlying
+// error with errors.Is.
+type ParseError struct {
+	// Kind is ErrRead, ErrLimitExceeded or ErrCanceled.
+	Kind error
+
+	// Err is the underlying error.
+	Err error
+
+	// Offset is the number of bytes of the document which were read before
+	// the error.
+	Offset int64
+}
+
+func newParseError(err error, offset int64) *ParseError {
+	kind := ErrRead
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		kind = ErrCanceled
+	}
+	for _, limitErr := range []error{html.ErrBufferExceeded, ErrMaxBytes, ErrMaxTokens, ErrMaxDepth, ErrMaxTextBlocks} {
+		if errors.Is(err, limitErr) {
+			kind = ErrLimitExceeded
+		}
+	}
+	return &ParseError{
+		Kind:   kind,
+		Err:    err,
+		Offset: offset,
+	}
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v after %d bytes: %v", e.Kind, e.Offset, e.Err)
+}
+
+// Unwrap returns the kind and the underlying error.
+func (e *ParseError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// countingReader counts the bytes read from a reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.n += int64(n)
+	return
+}
@@ -0,0 +1,61 @@
+package boilerpipe
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/net/html"
+)
+
+func TestParseDocumentPartial(t *testing.T) {
+	const s = `<html><head><title>The title</title></head><body><p>This is the text of the page which was read before the error.</p>`
+
+	r := io.MultiReader(strings.NewReader(s), iotest.ErrReader(io.ErrUnexpectedEOF))
+	doc, err := ParseDocument(r)
+	if doc == nil {
+		t.Fatal("expected partial document")
+	}
+	if exp := "The title"; doc.Title != exp {
+		t.Errorf("expected title '%s' but got '%s'", exp, doc.Title)
+	}
+	if exp := "This is the text of the page which was read before the error."; doc.Text(true, true) != exp {
+		t.Errorf("expected text '%s' but got '%s'", exp, doc.Text(true, true))
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected ParseError but got %v", err)
+	}
+	if parseErr.Offset != int64(len(s)) {
+		t.Errorf("expected offset %d but got %d", len(s), parseErr.Offset)
+	}
+	if !errors.Is(err, ErrRead) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected read error but got %v", err)
+	}
+	if errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected error not to be a limit error")
+	}
+}
+
+func TestParseErrorKind(t *testing.T) {
+	tests := []struct {
+		err error
+		exp error
+	}{
+		{io.ErrUnexpectedEOF, ErrRead},
+		{html.ErrBufferExceeded, ErrLimitExceeded},
+	}
+
+	for _, test := range tests {
+		err := newParseError(test.err, 0)
+		if err.Kind != test.exp {
+			t.Errorf("%v: expected kind '%v' but got '%v'", test.err, test.exp, err.Kind)
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("%v: expected error to match the underlying error", test.err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package boilerpipe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A Trace records why the filters of a Pipeline changed the text blocks of a
+// document. Set Document.Trace to a new Trace before processing the document
+// to record it.
+type Trace struct {
+	// Filters are the filters which processed the document, in order.
+	// Filters of nested pipelines are recorded in place of the pipeline.
+	Filters []FilterTrace `json:"filters"`
+
+	// blocks are copies of the text blocks before the current filter, and
+	// reasons are the explanations the current filter gave for its
+	// decisions, by the offset of the blocks.
+	blocks  []*TextBlock
+	reasons map[int][]string
+}
+
+// A FilterTrace is the text blocks a filter changed.
+type FilterTrace struct {
+	FilterName string       `json:"filterName"`
+	HasChanged bool         `json:"hasChanged"`
+	Blocks     []BlockTrace `json:"blocks"`
+}
+
+// A BlockTrace is a text block a filter changed, or which it explained its
+// decision for, along with why.
+type BlockTrace struct {
+	// Offset is the OffsetBlocksStart of the block before the filter.
+	Offset int `json:"offset"`
+
+	// Text is the text of the block before the filter.
+	Text string `json:"text"`
+
+	// WasContent and IsContent are whether the block was content before
+	// and after the filter.
+	WasContent bool `json:"wasContent"`
+	IsContent  bool `json:"isContent"`
+
+	// Removed is true if the filter removed the block, or merged it into
+	// another block.
+	Removed bool `json:"removed"`
+
+	LabelsAdded   []Label `json:"labelsAdded"`
+	LabelsRemoved []Label `json:"labelsRemoved"`
+
+	// Reasons are the explanations the filter gave for its decisions about
+	// the block, such as the rule and values which classified it.
+	Reasons []string `json:"reasons"`
+}
+
+// hook returns the hook which records the trace of a filter.
+func (t *Trace) hook() Hook {
+	return Hook{
+		BeforeFilter: t.beforeFilter,
+		AfterFilter:  t.afterFilter,
+	}
+}
+
+// beforeFilter records the text blocks of the document before a filter.
+func (t *Trace) beforeFilter(name string, doc *Document) {
+	t.blocks = t.blocks[:0]
+	for _, tb := range doc.TextBlocks {
+		t.blocks = append(t.blocks, tb.Clone())
+	}
+	t.reasons = make(map[int][]string)
+}
+
+// afterFilter records the text blocks the filter changed since
+// beforeFilter, and those it explained its decisions for.
+func (t *Trace) afterFilter(name string, doc *Document, hasChanged bool) {
+	ft := FilterTrace{
+		FilterName: name,
+		HasChanged: hasChanged,
+		Blocks:     make([]BlockTrace, 0),
+	}
+
+	changed := make(map[int]bool)
+	for _, d := range DiffTextBlocks(t.blocks, doc.TextBlocks) {
+		changed[d.offset()] = true
+		ft.Blocks = append(ft.Blocks, newBlockTrace(d, t.reasons[d.offset()]))
+	}
+	for _, tb := range t.blocks {
+		if reasons := t.reasons[tb.OffsetBlocksStart]; len(reasons) > 0 && !changed[tb.OffsetBlocksStart] {
+			ft.Blocks = append(ft.Blocks, newBlockTrace(TextBlockDiff{Before: tb, After: tb}, reasons))
+		}
+	}
+	sort.SliceStable(ft.Blocks, func(i, j int) bool {
+		return ft.Blocks[i].Offset < ft.Blocks[j].Offset
+	})
+
+	t.Filters = append(t.Filters, ft)
+	t.blocks = t.blocks[:0]
+	t.reasons = nil
+}
+
+func newBlockTrace(d TextBlockDiff, reasons []string) BlockTrace {
+	bt := BlockTrace{
+		Offset:  d.offset(),
+		Removed: d.After == nil,
+		Reasons: reasons,
+	}
+	if d.Before != nil {
+		bt.Text = d.Before.Text
+		bt.WasContent = d.Before.IsContent
+	} else {
+		bt.Text = d.After.Text
+	}
+	if !bt.Removed {
+		bt.IsContent = d.After.IsContent
+		bt.LabelsAdded = d.LabelsAdded()
+		bt.LabelsRemoved = d.LabelsRemoved()
+	}
+	return bt
+}
+
+// explain records why the current filter made a decision about a text block
+// if the document is being traced.
+func (doc *Document) explain(tb *TextBlock, format string, args ...interface{}) {
+	if doc.Trace == nil || doc.Trace.reasons == nil {
+		return
+	}
+	doc.Trace.reasons[tb.OffsetBlocksStart] = append(doc.Trace.reasons[tb.OffsetBlocksStart], fmt.Sprintf(format, args...))
+}
+
+// WriteTo writes the trace as text, one filter at a time, listing the blocks
+// each filter changed and why.
+func (t *Trace) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	for i, ft := range t.Filters {
+		changed := "unchanged"
+		if ft.HasChanged {
+			changed = "changed"
+		}
+		fmt.Fprintf(bw, "%03d %s (%s)\n", i+1, ft.FilterName, changed)
+
+		for _, bt := range ft.Blocks {
+			fmt.Fprintf(bw, "  block %d: %s", bt.Offset, contentName(bt.WasContent))
+			if bt.Removed {
+				fmt.Fprint(bw, " -> removed")
+			} else if bt.IsContent != bt.WasContent {
+				fmt.Fprintf(bw, " -> %s", contentName(bt.IsContent))
+			}
+			for _, label := range bt.LabelsAdded {
+				fmt.Fprintf(bw, " +%s", label)
+			}
+			for _, label := range bt.LabelsRemoved {
+				fmt.Fprintf(bw, " -%s", label)
+			}
+			fmt.Fprintf(bw, "\n    %q\n", snippet(bt.Text, 80))
+			for _, reason := range bt.Reasons {
+				fmt.Fprintf(bw, "    %s\n", reason)
+			}
+		}
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+func contentName(isContent bool) string {
+	if isContent {
+		return "content"
+	}
+	return "boilerplate"
+}
+
+// snippet returns the first n runes of the text on a single line.
+func snippet(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > n {
+		return string(runes[:n]) + "..."
+	}
+	return text
+}
+
+// countingWriter counts the bytes written to a writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n += int64(n)
+	return
+}
@@ -0,0 +1,147 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const explainTestHTML = `<html>
+<head><title>The headline of the story</title></head>
+<body>
+<div><a href="/">Home</a> <a href="/news">News</a> <a href="/sports">Sports</a></div>
+<h1>The headline of the story</h1>
+<p>This is the first paragraph of the story, which has enough words to be classified as content by the classifier.</p>
+<p>This is the second paragraph of the story, which also has enough words to be classified as content by the classifier.</p>
+</body>
+</html>`
+
+func TestTrace(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(explainTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline := &Pipeline{
+		PipelineName: "Test",
+		Filters: []Filter{
+			TerminatingBlocks(),
+			DocumentTitleMatchClassifier(),
+			NumWordsRulesClassifier(),
+			BoilerplateBlock(),
+		},
+	}
+
+	doc.Trace = &Trace{}
+	pipeline.Process(doc)
+
+	if l := len(doc.Trace.Filters); l != len(pipeline.Filters) {
+		t.Fatalf("expected %d filters but got %d", len(pipeline.Filters), l)
+	}
+
+	find := func(name string, offset int) *BlockTrace {
+		for _, ft := range doc.Trace.Filters {
+			if ft.FilterName != name {
+				continue
+			}
+			for i := range ft.Blocks {
+				if ft.Blocks[i].Offset == offset {
+					return &ft.Blocks[i]
+				}
+			}
+		}
+		return nil
+	}
+
+	bt := find("DocumentTitleMatchClassifier", 1)
+	if bt == nil {
+		t.Fatal("expected headline to be traced")
+	}
+	if exp := []Label{LabelTitle}; !reflect.DeepEqual(bt.LabelsAdded, exp) {
+		t.Errorf("expected labels added %v but got %v", exp, bt.LabelsAdded)
+	}
+
+	bt = find("NumWordsRulesClassifier", 0)
+	if bt == nil || bt.IsContent || len(bt.Reasons) != 1 {
+		t.Fatalf("expected links to be classified as boilerplate but got %+v", bt)
+	}
+	if exp := "classify boilerplate: link density > 0.333 (link density 1.000"; !strings.HasPrefix(bt.Reasons[0], exp) {
+		t.Errorf("expected reason '%s' but got '%s'", exp, bt.Reasons[0])
+	}
+
+	bt = find("NumWordsRulesClassifier", 2)
+	if bt == nil || bt.WasContent || !bt.IsContent {
+		t.Errorf("expected paragraph to be classified as content but got %+v", bt)
+	}
+
+	bt = find("BoilerplateBlock", 0)
+	if bt == nil || !bt.Removed {
+		t.Errorf("expected links to be removed but got %+v", bt)
+	}
+
+	buf := &bytes.Buffer{}
+	n, err := doc.Trace.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes written but got %d", buf.Len(), n)
+	}
+	for _, exp := range []string{
+		"002 DocumentTitleMatchClassifier (changed)\n  block 1: boilerplate +LabelTitle\n",
+		"  block 0: boilerplate -> removed\n    \"Home News Sports\"\n    removed boilerplate\n",
+	} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("expected explanation to contain '%s' but got '%s'", exp, buf.String())
+		}
+	}
+}
+
+func TestTraceUnchangedContent(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(explainTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	traced := doc.Clone()
+	traced.Trace = &Trace{}
+
+	ArticlePipeline.Process(doc)
+	ArticlePipeline.Process(traced)
+
+	if act, exp := traced.Content(), doc.Content(); act != exp {
+		t.Errorf("expected content '%s' but got '%s'", exp, act)
+	}
+	if l := len(traced.Trace.Filters); l != len(ArticlePipeline.Filters) {
+		t.Errorf("expected %d filters but got %d", len(ArticlePipeline.Filters), l)
+	}
+}
+
+func TestTraceNestedPipeline(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(explainTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline := &Pipeline{
+		PipelineName: "Outer",
+		Filters: []Filter{
+			&Pipeline{
+				PipelineName: "Inner",
+				Filters:      []Filter{NumWordsRulesClassifier()},
+			},
+			BoilerplateBlock(),
+		},
+	}
+
+	doc.Trace = &Trace{}
+	pipeline.Process(doc)
+
+	var names []string
+	for _, ft := range doc.Trace.Filters {
+		names = append(names, ft.FilterName)
+	}
+	if exp := []string{"NumWordsRulesClassifier", "BoilerplateBlock"}; !reflect.DeepEqual(names, exp) {
+		t.Errorf("expected filters %v but got %v", exp, names)
+	}
+}
@@ -0,0 +1,191 @@
+package boilerpipe
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+)
+
+// An Article is the result of extracting the article of a document with
+// Extract.
+type Article struct {
+	// Title is the title of the document.
+	Title string `json:"title"`
+
+	// Headline is the title of the document without its site name.
+	Headline string `json:"headline"`
+
+	// Description is the description of the document.
+	Description string `json:"description"`
+
+	// Lead is the first paragraph of the content.
+	Lead string `json:"lead"`
+
+	// Author is the name of the most likely author of the article, and
+	// Authors are the names of all of its authors, from the most to the least
+	// likely.
+	Author  string   `json:"author"`
+	Authors []string `json:"authors"`
+
+	// Date is the date the article was published, which falls back to the
+	// date of its URL if the document has none. DateModified is the date it
+	// was last modified. Either is the zero time if unknown.
+	Date         time.Time `json:"date"`
+	DateModified time.Time `json:"dateModified"`
+
+	// Language is the ISO 639-1 code of the document's language, or an empty
+	// string if unknown.
+	Language string `json:"language"`
+
+	// SiteName is the name of the site the article was published on.
+	SiteName string `json:"siteName"`
+
+	// URL is the source URL given with WithURL, and CanonicalURL is the
+	// canonical URL of the document. Either is nil if unknown.
+	URL          *normurl.URL `json:"url"`
+	CanonicalURL *normurl.URL `json:"canonicalUrl"`
+
+	// Content is the text of the article.
+	Content string `json:"content"`
+
+	// Tables are the data tables of the article's content.
+	Tables []*Table `json:"tables"`
+
+	// Pages is the number of pages the article was merged from.
+	Pages int `json:"pages"`
+
+	// Document is the processed document the article was extracted from.
+	Document *Document `json:"-"`
+}
+
+// An Option configures how Extract extracts an article.
+type Option func(*extractOptions)
+
+type extractOptions struct {
+	ctx          context.Context
+	url          *normurl.URL
+	filter       Filter
+	parseOptions *ParseOptions
+	fetcher      Fetcher
+	maxPages     int
+	trace        *Trace
+}
+
+// WithURL sets the URL the document was retrieved from, which is used to
+// resolve its relative URLs and as a fallback for its date.
+func WithURL(u *normurl.URL) Option {
+	return func(o *extractOptions) { o.url = u }
+}
+
+// WithPipeline sets the filter the document is processed with. The default is
+// ArticlePipeline.
+func WithPipeline(filter Filter) Option {
+	return func(o *extractOptions) { o.filter = filter }
+}
+
+// WithParseOptions sets the limits used when parsing the document. See
+// ParseDocumentOptions.
+func WithParseOptions(opts *ParseOptions) Option {
+	return func(o *extractOptions) { o.parseOptions = opts }
+}
+
+// WithContext sets the context of the extraction, which stops parsing and
+// processing the document and fetching its pages once it is done.
+func WithContext(ctx context.Context) Option {
+	return func(o *extractOptions) { o.ctx = ctx }
+}
+
+// WithPages fetches up to maxPages following pages of the document with the
+// fetcher, and merges them into the article. See FetchPagesContext.
+func WithPages(fetcher Fetcher, maxPages int) Option {
+	return func(o *extractOptions) {
+		o.fetcher = fetcher
+		o.maxPages = maxPages
+	}
+}
+
+// WithTrace records why the pipeline changed the text blocks of the document
+// in the trace. See Trace.
+func WithTrace(t *Trace) Option {
+	return func(o *extractOptions) { o.trace = t }
+}
+
+// Extract parses the document read from r, processes it with the pipeline
+// and returns its article.
+//
+// If parsing stops before the end of the document the article of the part
+// which was parsed is returned along with a *ParseError. If fetching the
+// following pages fails the article of the pages merged until then is
+// returned along with the error. Any other error returns a nil article, and is
+// joined with the *ParseError if parsing stopped too.
+func Extract(r io.Reader, opts ...Option) (*Article, error) {
+	o := &extractOptions{
+		ctx:    context.Background(),
+		filter: ArticlePipeline,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	doc, parseErr := ParseDocumentContext(o.ctx, r, o.parseOptions)
+	if o.url != nil {
+		doc.SetURL(o.url)
+	}
+	doc.Trace = o.trace
+
+	if cf, ok := o.filter.(ContextFilter); ok {
+		if _, err := cf.ProcessContext(o.ctx, doc); err != nil {
+			if parseErr != nil {
+				err = errors.Join(parseErr, err)
+			}
+			return nil, err
+		}
+	} else {
+		o.filter.Process(doc)
+	}
+
+	pages := 1
+	if o.fetcher != nil && o.maxPages > 0 && parseErr == nil {
+		n, err := FetchPagesContext(o.ctx, doc, o.fetcher, o.filter, o.maxPages)
+		pages += n
+		if err != nil {
+			return newArticle(doc, pages), err
+		}
+	}
+
+	return newArticle(doc, pages), parseErr
+}
+
+func newArticle(doc *Document, pages int) *Article {
+	authors := make([]string, 0, len(doc.Authors))
+	for _, a := range doc.Authors {
+		authors = append(authors, a.Name)
+	}
+
+	tables := doc.ContentTables()
+	if tables == nil {
+		tables = make([]*Table, 0)
+	}
+
+	return &Article{
+		Title:        doc.Title,
+		Headline:     doc.CleanTitle,
+		Description:  doc.Description,
+		Lead:         doc.Lead(),
+		Author:       doc.Author,
+		Authors:      authors,
+		Date:         doc.Date,
+		DateModified: doc.DateModified,
+		Language:     doc.Language,
+		SiteName:     doc.SiteName,
+		URL:          doc.URL,
+		CanonicalURL: doc.CanonicalURL,
+		Content:      doc.Content(),
+		Tables:       tables,
+		Pages:        pages,
+		Document:     doc,
+	}
+}
@@ -0,0 +1,184 @@
+package boilerpipe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+)
+
+const extractTestHTML = `<html>
+<head><title>The headline of the story | Example News</title></head>
+<body>
+<article>
+<h1>The headline of the story</h1>
+<p>This is the first paragraph of the story, which has enough words to be classified as content by the classifier.</p>
+<p>This is the second paragraph of the story, which also has enough words to be classified as content by the classifier.</p>
+</article>
+</body>
+</html>`
+
+func TestExtract(t *testing.T) {
+	f, err := os.Open("testdata/1.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	article, err := Extract(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ParseDocument(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ArticlePipeline.Process(doc)
+
+	if article.Title != doc.Title {
+		t.Errorf("expected title '%s' but got '%s'", doc.Title, article.Title)
+	}
+	if article.Content != doc.Content() {
+		t.Errorf("expected content '%s' but got '%s'", doc.Content(), article.Content)
+	}
+	if article.Pages != 1 {
+		t.Errorf("expected 1 page but got %d", article.Pages)
+	}
+}
+
+func TestExtractURLDate(t *testing.T) {
+	u, err := normurl.Parse("https://example.com/news/2017/apr/20/the-headline-of-the-story")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	article, err := Extract(strings.NewReader(extractTestHTML), WithURL(u))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := time.Date(2017, time.April, 20, 0, 0, 0, 0, time.UTC); !article.Date.Equal(exp) {
+		t.Errorf("expected date %v but got %v", exp, article.Date)
+	}
+	if article.URL != u {
+		t.Errorf("expected URL %v but got %v", u, article.URL)
+	}
+	if exp := "The headline of the story"; article.Headline != exp {
+		t.Errorf("expected headline '%s' but got '%s'", exp, article.Headline)
+	}
+}
+
+func TestExtractPipeline(t *testing.T) {
+	pipeline := &Pipeline{
+		PipelineName: "Empty",
+	}
+
+	article, err := Extract(strings.NewReader(extractTestHTML), WithPipeline(pipeline))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Content != "" {
+		t.Errorf("expected no content but got '%s'", article.Content)
+	}
+}
+
+func TestExtractPartial(t *testing.T) {
+	r := io.MultiReader(strings.NewReader(extractTestHTML), iotest.ErrReader(io.ErrUnexpectedEOF))
+
+	article, err := Extract(r)
+	if !errors.Is(err, ErrRead) {
+		t.Errorf("expected read error but got %v", err)
+	}
+	if article == nil {
+		t.Fatal("expected partial article")
+	}
+	if !strings.Contains(article.Content, "second paragraph") {
+		t.Errorf("expected content but got '%s'", article.Content)
+	}
+}
+
+func TestExtractPartialCanceled(t *testing.T) {
+	r := io.MultiReader(strings.NewReader(extractTestHTML), iotest.ErrReader(io.ErrUnexpectedEOF))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	processed := 0
+	pipeline := &Pipeline{
+		PipelineName: "Cancel",
+		Filters:      []Filter{cancelFilter{cancel, &processed}, BoilerplateBlock()},
+	}
+
+	// Both the read error and the cancellation are returned
+	article, err := Extract(r, WithContext(ctx), WithPipeline(pipeline))
+	if !errors.Is(err, ErrRead) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected read error and cancellation but got %v", err)
+	}
+	if article != nil {
+		t.Errorf("expected no article but got %+v", article)
+	}
+}
+
+func TestExtractPagesError(t *testing.T) {
+	errFetch := errors.New("fetch error")
+	fetched := 0
+	fetcher := FetcherFunc(func(u *normurl.URL) (io.ReadCloser, error) {
+		fetched++
+		if fetched > 1 {
+			return nil, errFetch
+		}
+		return io.NopCloser(strings.NewReader(paginationPage(2))), nil
+	})
+
+	article, err := Extract(strings.NewReader(paginationPage(1)), WithPages(fetcher, 10))
+	if !errors.Is(err, errFetch) {
+		t.Errorf("expected fetch error but got %v", err)
+	}
+	if article == nil {
+		t.Fatal("expected the article of the merged pages")
+	}
+	if article.Pages != 2 {
+		t.Errorf("expected 2 pages but got %d", article.Pages)
+	}
+	for _, exp := range []string{"page 1 of the story", "page 2 of the story"} {
+		if !strings.Contains(article.Content, exp) {
+			t.Errorf("expected content to contain '%s' but got '%s'", exp, article.Content)
+		}
+	}
+}
+
+func TestArticleJSON(t *testing.T) {
+	article, err := Extract(strings.NewReader(extractTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(article)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"title", "headline", "description", "lead", "author", "authors", "date", "dateModified", "language", "siteName", "url", "canonicalUrl", "content", "tables", "pages"} {
+		if _, exists := m[key]; !exists {
+			t.Errorf("expected key '%s'", key)
+		}
+	}
+	if _, exists := m["Document"]; exists {
+		t.Error("expected document not to be encoded")
+	}
+}
@@ -1,6 +1,7 @@
 package boilerpipe
 
 import (
+	"context"
 	"math"
 	"regexp"
 	"strings"
@@ -11,18 +12,101 @@ import (
 type Pipeline struct {
 	PipelineName string
 	Filters      []Filter
+
+	// Hooks are called before and after each filter of the pipeline, in
+	// order. The hooks of a pipeline aren't called for the filters of the
+	// pipelines nested in it.
+	Hooks []Hook
 }
 
-// Statically check that *Pipeline satisfies the Filter interface.
-var _ Filter = (*Pipeline)(nil)
+// A Hook is a pair of callbacks run around each filter of a Pipeline, such as
+// to collect metrics. Either may be nil.
+type Hook struct {
+	// BeforeFilter is called with the name of the filter before it processes
+	// the document.
+	BeforeFilter func(name string, doc *Document)
+
+	// AfterFilter is called with the name of the filter after it processes
+	// the document, along with whether the filter changed it. It is also
+	// called if the filter returned an error.
+	AfterFilter func(name string, doc *Document, hasChanged bool)
+}
+
+// Statically check that *Pipeline satisfies the Filter and ContextFilter
+// interfaces.
+var (
+	_ Filter        = (*Pipeline)(nil)
+	_ ContextFilter = (*Pipeline)(nil)
+)
 
 // Name returns the pipeline name.
 func (pipeline *Pipeline) Name() string { return pipeline.PipelineName }
 
 // Process runs a document through the collection of filters in the pipeline.
+// The hooks of the pipeline are called around each filter, and if the
+// document has a Trace the changes each filter makes are recorded.
 func (pipeline *Pipeline) Process(doc *Document) (hasChanged bool) {
 	for _, filter := range pipeline.Filters {
-		hasChanged = filter.Process(doc) || hasChanged
+		changed, _ := pipeline.processFilter(filter, doc, func() (bool, error) {
+			return filter.Process(doc), nil
+		})
+		hasChanged = changed || hasChanged
+	}
+	return
+}
+
+// ProcessContext runs a document through the collection of filters in the
+// pipeline, stopping before the next filter once the context is done and
+// returning the context's error. Filters which are ContextFilters, such as
+// nested pipelines, are given the context. Hooks and traces are handled like
+// Process.
+func (pipeline *Pipeline) ProcessContext(ctx context.Context, doc *Document) (hasChanged bool, err error) {
+	for _, filter := range pipeline.Filters {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var changed bool
+		changed, err = pipeline.processFilter(filter, doc, func() (bool, error) {
+			if cf, ok := filter.(ContextFilter); ok {
+				return cf.ProcessContext(ctx, doc)
+			}
+			return filter.Process(doc), nil
+		})
+		hasChanged = changed || hasChanged
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// processFilter processes the document with the filter by calling process,
+// and calls the hooks of the pipeline, and those of the document's Trace,
+// around it. Nested pipelines trace their own filters.
+func (pipeline *Pipeline) processFilter(filter Filter, doc *Document, process func() (bool, error)) (hasChanged bool, err error) {
+	hooks := pipeline.Hooks
+	switch filter.(type) {
+	case *Pipeline, *TracingPipeline:
+	default:
+		if doc.Trace != nil {
+			hooks = append(hooks[:len(hooks):len(hooks)], doc.Trace.hook())
+		}
+	}
+
+	name := filter.Name()
+	for _, hook := range hooks {
+		if hook.BeforeFilter != nil {
+			hook.BeforeFilter(name, doc)
+		}
+	}
+
+	hasChanged, err = process()
+
+	for _, hook := range hooks {
+		if hook.AfterFilter != nil {
+			hook.AfterFilter(name, doc, hasChanged)
+		}
 	}
 	return
 }
@@ -45,6 +129,30 @@ var ArticlePipeline = &Pipeline{
 	},
 }
 
+// ExtendedArticlePipeline is ArticlePipeline along with the filters which
+// change the title and content compared to it, such as using the heading
+// nearest the content as the title and excluding bylines from the content, so
+// that they are opt-in.
+var ExtendedArticlePipeline = &Pipeline{
+	PipelineName: "ExtendedArticle",
+	Filters: []Filter{
+		TerminatingBlocks(),
+		DocumentTitleMatchClassifier(),
+		NumWordsRulesClassifier(),
+		HeadingTitleClassifier(),
+		IgnoreBlocksAfterContent(),
+		TrailingHeadlineToBoilerplate(),
+		BlockProximityFusionMaxDistanceOne(),
+		BoilerplateBlock(),
+		BlockProximityFusionMaxDistanceOneContentOnlySameTagLevel(),
+		KeepLargestBlocks(),
+		ExpandTitleToContent(),
+		LargeBlockSameTagLevelToContent(),
+		ListAtEnd(),
+		BylineToBoilerplate(),
+	},
+}
+
 // Filter is the interface that processes documents and notifies if it has
 // changed.
 type Filter interface {
@@ -55,6 +163,16 @@ type Filter interface {
 	Process(doc *Document) (hasChanged bool)
 }
 
+// A ContextFilter is a Filter which can stop processing a document once a
+// context is done.
+type ContextFilter interface {
+	Filter
+
+	// ProcessContext is like Process, but returns the context's error if it
+	// is done before processing is complete.
+	ProcessContext(ctx context.Context, doc *Document) (hasChanged bool, err error)
+}
+
 func TerminatingBlocks() Filter { return terminatingBlocks{} }
 
 type terminatingBlocks struct{}
@@ -88,15 +206,18 @@ func (terminatingBlocks) Process(doc *Document) bool {
 					strings.Contains(textLC, "have your say") ||
 					strings.Contains(textLC, "reader comments") ||
 					strings.Contains(textLC, "rätta artikeln") ||
-					textLC == "thanks for your comments - this feedback is now closed" {
+					textLC == "thanks for your comments - this feedback is now closed" ||
+					isTerminatingPhrase(doc.Language, textLC) {
 
 					tb.AddLabels(LabelIndicatesEndOfText)
+					doc.explain(tb, "%d words starting with a phrase which ends the text", numWords)
 					hasChanged = true
 				}
 
 			} else if tb.LinkDensity() == 1.0 {
 				if text == "Comment" {
 					tb.AddLabels(LabelIndicatesEndOfText)
+					doc.explain(tb, "a \"Comment\" link ends the text")
 				}
 			}
 		}
@@ -134,82 +255,194 @@ type documentTitleMatchClassifier struct{}
 func (documentTitleMatchClassifier) Name() string { return "DocumentTitleMatchClassifier" }
 
 func (filter documentTitleMatchClassifier) Process(doc *Document) bool {
-	if len(doc.Title) == 0 {
+	potentialTitles := make(map[string]bool)
+	for _, pot := range titleCandidates(doc.Title) {
+		if pot = normalizeTitle(pot); len(pot) > 0 {
+			potentialTitles[pot] = true
+		}
+	}
+
+	if len(potentialTitles) == 0 {
 		return false
 	}
 
-	title := doc.Title
+	hasChanged := false
+
+	for i := 0; i < len(doc.TextBlocks); i++ {
+		tb := doc.TextBlocks[i]
+
+		if matchesTitle(potentialTitles, tb.Text) {
+			tb.AddLabels(LabelTitle)
+			doc.explain(tb, "text matches the document title %q", doc.Title)
+			hasChanged = true
+			break
+		}
+	}
+
+	return hasChanged
+}
+
+// normalizeTitle returns the lowercase title without apostrophes and
+// non-breaking spaces, for comparing titles with text.
+func normalizeTitle(title string) string {
 	title = strings.Replace(title, "\u00a0", " ", -1)
 	title = strings.Replace(title, "'", "", -1)
 	title = strings.TrimSpace(title)
-	title = strings.ToLower(title)
+	return strings.ToLower(title)
+}
+
+var reTitlePunctuation = regexp.MustCompile("[\\?\\!\\.\\-\\:]+")
+
+// matchesTitle returns true if the normalized text, with or without its
+// punctuation, is one of the normalized potential titles.
+func matchesTitle(potentialTitles map[string]bool, text string) bool {
+	text = normalizeTitle(text)
+	if potentialTitles[text] {
+		return true
+	}
 
+	text = strings.TrimSpace(reTitlePunctuation.ReplaceAllString(text, ""))
+	return potentialTitles[text]
+}
+
+// titleCandidates returns the parts of a document title which may be the
+// actual title of its content, starting with the whole title.
+func titleCandidates(title string) (candidates []string) {
+	title = strings.TrimSpace(strings.Replace(title, "\u00a0", " ", -1))
 	if len(title) == 0 {
-		return false
+		return
 	}
 
 	potentialTitles := make(map[string]bool)
-	potentialTitles[title] = true
+	add := func(pot string) {
+		if len(pot) > 0 && !potentialTitles[pot] {
+			potentialTitles[pot] = true
+			candidates = append(candidates, pot)
+		}
+	}
 
-	var pot string
+	add(title)
 
-	pot = getLongestPart(title, "[ ]*[\\|»|-][ ]*")
-	if len(pot) > 0 {
-		potentialTitles[pot] = true
-	}
-	pot = getLongestPart(title, "[ ]*[\\|»|:][ ]*")
-	if len(pot) > 0 {
-		potentialTitles[pot] = true
-	}
-	pot = getLongestPart(title, "[ ]*[\\|»|:\\(\\)][ ]*")
-	if len(pot) > 0 {
-		potentialTitles[pot] = true
-	}
-	pot = getLongestPart(title, "[ ]*[\\|»|:\\(\\)\\-][ ]*")
-	if len(pot) > 0 {
-		potentialTitles[pot] = true
-	}
-	pot = getLongestPart(title, "[ ]*[\\|»|,|:\\(\\)\\-][ ]*")
-	if len(pot) > 0 {
-		potentialTitles[pot] = true
+	add(getLongestPart(title, "[ ]*[\\|»|-][ ]*"))
+	add(getLongestPart(title, "[ ]*[\\|»|:][ ]*"))
+	add(getLongestPart(title, "[ ]*[\\|»|:\\(\\)][ ]*"))
+	add(getLongestPart(title, "[ ]*[\\|»|:\\(\\)\\-][ ]*"))
+	add(getLongestPart(title, "[ ]*[\\|»|,|:\\(\\)\\-][ ]*"))
+	add(getLongestPart(title, "[ ]*[\\|»|,|:\\(\\)\\-\u00a0][ ]*"))
+
+	for _, pot := range potentialTitleParts(title, "[ ]+[\\|][ ]+", 4) {
+		add(pot)
 	}
-	pot = getLongestPart(title, "[ ]*[\\|»|,|:\\(\\)\\-\u00a0][ ]*")
-	if len(pot) > 0 {
-		potentialTitles[pot] = true
+	for _, pot := range potentialTitleParts(title, "[ ]+[\\-][ ]+", 4) {
+		add(pot)
 	}
 
-	addPotentialTitles(potentialTitles, title, "[ ]+[\\|][ ]+", 4)
-	addPotentialTitles(potentialTitles, title, "[ ]+[\\-][ ]+", 4)
+	add(removeFirst(title, " - [^\\-]+$"))
+	add(removeFirst(title, "^[^\\-]+ - "))
 
-	potentialTitles[removeFirst(title, " - [^\\-]+$")] = true
-	potentialTitles[removeFirst(title, "^[^\\-]+ - ")] = true
+	return
+}
 
-	hasChanged := false
+// HeadingTitleClassifier labels the heading block nearest the content as the
+// title, and uses its text as the document title, when the document has no
+// title or a generic one and no block has been labeled as the title by
+// DocumentTitleMatchClassifier. It must run after a classifier has marked the
+// content blocks. It's part of ExtendedArticlePipeline.
+func HeadingTitleClassifier() Filter { return headingTitleClassifier{} }
 
-	for i := 0; i < len(doc.TextBlocks); i++ {
-		tb := doc.TextBlocks[i]
+type headingTitleClassifier struct{}
 
-		text := tb.Text
-		text = strings.Replace(text, "\u00a0", " ", -1)
-		text = strings.Replace(text, "'", "", -1)
-		text = strings.TrimSpace(text)
-		text = strings.ToLower(text)
+func (headingTitleClassifier) Name() string { return "HeadingTitleClassifier" }
 
-		if _, contains := potentialTitles[text]; contains {
-			tb.AddLabels(LabelTitle)
-			hasChanged = true
-			break
+// maxHeadingTitleWords is the maximum number of words a heading can have to
+// be used as the title.
+const maxHeadingTitleWords = 30
+
+func (filter headingTitleClassifier) Process(doc *Document) bool {
+	if !isGenericTitle(doc) {
+		return false
+	}
+
+	contentStart := -1
+	for i, tb := range doc.TextBlocks {
+		if tb.HasLabel(LabelTitle) {
+			return false
 		}
+		if contentStart == -1 && tb.IsContent {
+			contentStart = i
+		}
+	}
 
-		text = strings.TrimSpace(regexp.MustCompile("[\\?\\!\\.\\-\\:]+").ReplaceAllString(text, ""))
-		if _, contains := potentialTitles[text]; contains {
-			tb.AddLabels(LabelTitle)
-			hasChanged = true
-			break
+	// Prefer level 1 headings, then any large heading, and within a level
+	// the heading nearest the start of the content. Headings after the
+	// start of the content are less likely to be the headline.
+	for _, label := range []Label{LabelHeading1, LabelHeading2, LabelHeading} {
+		best, bestDistance := -1, 0
+		for i, tb := range doc.TextBlocks {
+			if !tb.HasLabel(label) || tb.NumWords == 0 || tb.NumWords > maxHeadingTitleWords || tb.LinkDensity() > 0.5 {
+				continue
+			}
+
+			distance := 0
+			if contentStart != -1 {
+				distance = contentStart - i
+				if distance < 0 {
+					distance = -2 * distance
+				}
+			}
+			if best == -1 || distance < bestDistance {
+				best, bestDistance = i, distance
+			}
+		}
+
+		if best == -1 {
+			continue
 		}
+
+		tb := doc.TextBlocks[best]
+		tb.AddLabels(LabelTitle)
+		doc.explain(tb, "%s nearest the content (distance %d) of generic title %q", label, bestDistance, doc.Title)
+		doc.Title = strings.TrimSpace(tb.Text)
+		doc.CleanTitle = doc.Title
+		return true
 	}
 
-	return hasChanged
+	return false
+}
+
+// genericTitles are the lowercase placeholder titles used by templates and
+// single-page applications before the content is rendered.
+var genericTitles = map[string]bool{
+	"home":        true,
+	"homepage":    true,
+	"index":       true,
+	"untitled":    true,
+	"document":    true,
+	"loading":     true,
+	"loading...":  true,
+	"new tab":     true,
+	"react app":   true,
+	"vite app":    true,
+	"welcome":     true,
+	"article":     true,
+	"news":        true,
+	"blog":        true,
+	"page":        true,
+	"page title":  true,
+	"title":       true,
+	"home page":   true,
+	"main page":   true,
+	"single post": true,
+}
+
+// isGenericTitle returns true if the document title is empty, a placeholder,
+// or only the name of the site.
+func isGenericTitle(doc *Document) bool {
+	title := strings.ToLower(strings.Join(strings.Fields(doc.Title), " "))
+	if title == "" || genericTitles[title] {
+		return true
+	}
+	return doc.SiteName != "" && title == strings.ToLower(strings.TrimSpace(doc.SiteName))
 }
 
 func removeFirst(s string, pattern string) string {
@@ -220,22 +453,25 @@ func removeFirst(s string, pattern string) string {
 	return strings.Replace(s, m, "", 1)
 }
 
-func addPotentialTitles(potentialTitles map[string]bool, title string, pattern string, minWords int) {
-	parts := strings.Split(title, " ")
+// potentialTitleParts returns the parts of the title separated by the pattern
+// which have at least minWords words.
+func potentialTitleParts(title string, pattern string, minWords int) (potentialTitles []string) {
+	parts := regexp.MustCompile(pattern).Split(title, -1)
 	if len(parts) == 1 {
 		return
 	}
 
 	for _, p := range parts {
-		if strings.Contains(p, ".com") {
+		if strings.Contains(strings.ToLower(p), ".com") {
 			continue
 		}
 
 		numWords := len(regexp.MustCompile("[\b ]+").Split(p, -1))
 		if numWords >= minWords {
-			potentialTitles[p] = true
+			potentialTitles = append(potentialTitles, p)
 		}
 	}
+	return
 }
 
 func getLongestPart(title, pattern string) string {
@@ -248,7 +484,7 @@ func getLongestPart(title, pattern string) string {
 	longestPart := ""
 
 	for _, p := range parts {
-		if strings.Contains(p, ".com") {
+		if strings.Contains(strings.ToLower(p), ".com") {
 			continue
 		}
 
@@ -281,6 +517,7 @@ func (filter trailingHeadlineToBoilerplate) Process(doc *Document) bool {
 		if tb.IsContent {
 			if tb.HasLabel(LabelHeading) {
 				tb.IsContent = false
+				doc.explain(tb, "heading at the end of the content")
 				hasChanged = true
 			} else {
 				break
@@ -291,6 +528,30 @@ func (filter trailingHeadlineToBoilerplate) Process(doc *Document) bool {
 	return hasChanged
 }
 
+// BylineToBoilerplate marks the blocks labeled as bylines as boilerplate, so
+// that they are excluded from the content. It's part of
+// ExtendedArticlePipeline.
+func BylineToBoilerplate() Filter { return bylineToBoilerplate{} }
+
+type bylineToBoilerplate struct{}
+
+func (bylineToBoilerplate) Name() string { return "BylineToBoilerplate" }
+
+func (filter bylineToBoilerplate) Process(doc *Document) bool {
+	hasChanged := false
+
+	for _, tb := range doc.TextBlocks {
+		// Blocks which have been merged with content are kept
+		if tb.IsContent && tb.HasLabel(LabelByline) && tb.NumWords <= maxBylineWords {
+			tb.IsContent = false
+			doc.explain(tb, "byline of %d words (max %d)", tb.NumWords, maxBylineWords)
+			hasChanged = true
+		}
+	}
+
+	return hasChanged
+}
+
 func BlockProximityFusionMaxDistanceOne() Filter {
 	return &blockProximityFusionParams{"One", 1, false, false}
 }
@@ -373,6 +634,7 @@ func (filter *blockProximityFusionParams) Process(doc *Document) bool {
 			}
 
 			if merge {
+				doc.explain(tb, "merged into block %d at distance %d (max %d)", prevBlock.OffsetBlocksStart, diffBlocks, maxBlocksDistance)
 				prevBlock.MergeNext(tb)
 
 				// Remove merged text block
@@ -404,6 +666,7 @@ func (filter boilerplateBlock) Process(doc *Document) bool {
 		tb := doc.TextBlocks[i]
 
 		if tb.IsContent == false && tb.HasLabel(LabelTitle) == false {
+			doc.explain(tb, "removed boilerplate")
 			doc.TextBlocks = append(doc.TextBlocks[:i], doc.TextBlocks[i+1:]...)
 			i--
 			hasChanged = true
@@ -465,7 +728,11 @@ func (filter keepLargestBlocks) Process(doc *Document) bool {
 		if tb == largestBlock {
 			tb.IsContent = true
 			tb.AddLabels(LabelVeryLikelyContent)
+			doc.explain(tb, "largest content block of %d words", maxNumWords)
 		} else {
+			if tb.IsContent {
+				doc.explain(tb, "%d words compared to the largest block of %d words", tb.NumWords, maxNumWords)
+			}
 			tb.IsContent = isLargestBlock(maxNumWords, tb)
 			tb.AddLabels(LabelMightBeContent)
 		}
@@ -482,6 +749,7 @@ func (filter keepLargestBlocks) Process(doc *Document) bool {
 			} else if tl == level {
 				if tb.NumWords >= filter.minWords {
 					tb.IsContent = true
+					doc.explain(tb, "%d words (min %d) at the tag level %d of the largest block", tb.NumWords, filter.minWords, level)
 				}
 			}
 		}
@@ -496,6 +764,7 @@ func (filter keepLargestBlocks) Process(doc *Document) bool {
 			} else if tl == level {
 				if tb.NumWords >= filter.minWords {
 					tb.IsContent = true
+					doc.explain(tb, "%d words (min %d) at the tag level %d of the largest block", tb.NumWords, filter.minWords, level)
 				}
 			}
 		}
@@ -555,6 +824,7 @@ func (filter keepLargestFulltextBlock) Process(doc *Document) bool {
 
 		if tb == largestBlock {
 			tb.IsContent = true
+			doc.explain(tb, "largest full text block of %d words", max)
 		} else {
 			tb.IsContent = false
 			tb.AddLabels(LabelMightBeContent)
@@ -601,6 +871,7 @@ func (filter expandTitleToContent) Process(doc *Document) bool {
 		if tb.HasLabel(LabelMightBeContent) {
 			hasChanged = (tb.IsContent == false) || hasChanged
 			tb.IsContent = true
+			doc.explain(tb, "between the title and the start of the content")
 		}
 	}
 
@@ -636,6 +907,7 @@ func (filter largeBlockSameTagLevelToContent) Process(doc *Document) bool {
 		if tb.IsContent == false {
 			if tb.NumWords >= 100 && tb.TagLevel == tagLevel {
 				tb.IsContent = true
+				doc.explain(tb, "%d words (min 100) at the tag level %d of the very likely content", tb.NumWords, tagLevel)
 				hasChanged = true
 			}
 		}
@@ -669,6 +941,7 @@ func (filter ignoreBlocksAfterContent) Process(doc *Document) bool {
 		}
 		if eot && numWords >= filter.minNumWords {
 			foundEndOfText = true
+			doc.explain(tb, "end of the text after %d words of content (min %d)", numWords, filter.minNumWords)
 		}
 		if foundEndOfText {
 			hasChanged = true
@@ -702,26 +975,32 @@ func (filter numWordsRulesClassifier) Process(doc *Document) bool {
 		nextBlock = textBlockEmptyStart
 	}
 
-	hasChanged = classify(prevBlock, currentBlock, nextBlock) || hasChanged
+	hasChanged = classify(doc, prevBlock, currentBlock, nextBlock) || hasChanged
 
 	if nextBlock != textBlockEmptyStart {
 		for i := 2; i < len(doc.TextBlocks); i++ {
 			prevBlock = currentBlock
 			currentBlock = nextBlock
 			nextBlock = doc.TextBlocks[i]
-			hasChanged = classify(prevBlock, currentBlock, nextBlock) || hasChanged
+			hasChanged = classify(doc, prevBlock, currentBlock, nextBlock) || hasChanged
 		}
 		prevBlock = currentBlock
 		currentBlock = nextBlock
 		nextBlock = textBlockEmptyEnd
-		hasChanged = classify(prevBlock, currentBlock, nextBlock) || hasChanged
+		hasChanged = classify(doc, prevBlock, currentBlock, nextBlock) || hasChanged
 	}
 
 	return hasChanged
 }
 
-func classify(prev, curr, next *TextBlock) bool {
-	isContent := false
+// classify classifies the current text block as content or not by its number
+// of words and link density, and those of the blocks around it, according to
+// boilerpipe's NumWordsRulesClassifier decision tree.
+func classify(doc *Document, prev, curr, next *TextBlock) bool {
+	var (
+		isContent bool
+		rule      string
+	)
 
 	if curr.LinkDensity() <= 0.333333 {
 		if prev.LinkDensity() <= 0.555556 {
@@ -729,31 +1008,43 @@ func classify(prev, curr, next *TextBlock) bool {
 				if next.NumWords <= 15 {
 					if prev.NumWords <= 4 {
 						isContent = false
+						rule = "words <= 16, next words <= 15 and previous words <= 4"
 					} else {
 						isContent = true
+						rule = "words <= 16, next words <= 15 and previous words > 4"
 					}
 				} else {
 					isContent = true
+					rule = "words <= 16 and next words > 15"
 				}
 			} else {
 				isContent = true
+				rule = "words > 16"
 			}
 		} else {
 			if curr.NumWords <= 40 {
 				if next.NumWords <= 17 {
 					isContent = false
+					rule = "previous link density > 0.556, words <= 40 and next words <= 17"
 				} else {
 					isContent = true
+					rule = "previous link density > 0.556, words <= 40 and next words > 17"
 				}
 			} else {
 				isContent = true
+				rule = "previous link density > 0.556 and words > 40"
 			}
 		}
 	} else {
 		isContent = false
+		rule = "link density > 0.333"
 	}
 
 	curr.IsContent = isContent
+	if doc.Trace != nil {
+		doc.explain(curr, "classify %s: %s (link density %.3f, previous link density %.3f, words %d, previous words %d, next words %d)",
+			contentName(isContent), rule, curr.LinkDensity(), prev.LinkDensity(), curr.NumWords, prev.NumWords, next.NumWords)
+	}
 	return isContent
 }
 
@@ -786,6 +1077,7 @@ func (filter listAtEnd) Process(doc *Document) bool {
 			if tb.TagLevel > tagLevel && tb.HasLabel(LabelMightBeContent) &&
 				tb.HasLabel(LabelList) && tb.LinkDensity() == 0.0 {
 				tb.IsContent = true
+				doc.explain(tb, "list without links after the very likely content")
 				hasChanged = true
 			} else {
 				tagLevel = math.MaxInt32
@@ -1,11 +1,13 @@
 package boilerpipe
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -106,6 +108,7 @@ func ExampleParseDocument() {
 	// 5Lqk6YCa6L+Q6L6T6YOo77ya5Lik5bm05YaF5Yqb5LqJ5o+Q5YmN5Z+65pys5Y+W5raI6auY6YCf55yB55WM5pS26LS556uZCuS4reaWsOe9kTPmnIgyOOaXpeeUtSDku4rlubTmlL/lupzlt6XkvZzmiqXlkYrmj5Dlh7rvvIzkuKTlubTlhoXlj5bmtojlhajlm73pq5jpgJ/lhazot6/nnIHnlYzmlLbotLnnq5njgILkuqTpgJrov5DovpPpg6jmlrDpl7vlj5HoqIDkurrlkLTmmKXogJXku4rml6XlnKjosIjlj4rmraTlt6XkvZzmnIDmlrDov5vlsZXml7booajnpLrvvIzkuqTpgJrov5DovpPpg6jlt7LmiJDnq4vkuJPpobnlt6XkvZzmjIfmjKXpg6jvvIznoa7kv53kuKTlubTlhoXlipvkuonmj5DliY3ln7rmnKzlj5bmtojlhajlm73pq5jpgJ/lhazot6/nnIHnlYzmlLbotLnnq5njgIIK6LWE5paZ5Zu+77ya6auY6YCf5YWs6Lev5pS26LS556uZ44CC6YeR5rGJ5piVIOaRhCDlm77niYfmnaXmupDvvJrop4bop4nkuK3lm70KM+aciDI45pel77yM5Zu95paw5Yqe5Li+6KGM5paw6Ze75Y+R5biD5Lya77yM5Lqk6YCa6L+Q6L6T6YOo5pS/562W56CU56m25a6k5Li75Lu744CB5paw6Ze75Y+R6KiA5Lq65ZC05pil6ICV77yM5paw6Ze75Y+R6KiA5Lq65q+b5YGl5Zu057uV4oCc5o+Q6auY57u85ZCI5Lqk6YCa6L+Q6L6T572R57uc5pWI546H77yM6ZmN5L2O5Lqk6YCa6L+Q6L6T54mp5rWB5oiQ5pys4oCd5LuL57uN5pyJ5YWz5oOF5Ya144CCCuS7iuW5tOaUv+W6nOW3peS9nOaKpeWRiuS4reaPkOWHuuS4pOW5tOWGheWPlua2iOWFqOWbvemrmOmAn+WFrOi3r+ecgeeVjOaUtui0ueerme+8jOWcqOWbnuW6lOatpOW3peS9nOacgOaWsOi/m+WxleaXtu+8jOWQtOaYpeiAleS7i+e7je+8jOS4pOS8mue7k+adn+WQjueahOi/meauteaXtumXtO+8jOS6pOmAmui/kOi+k+mDqOe7j+WkmuasoeeglOeptumDqOe9su+8jOaYjuehruaKiuWPlua2iOWFqOWbvemrmOmAn+WFrOi3r+ecgeeVjOaUtui0ueermeW3peS9nOS9nOS4uuS7iuW5tOS6pOmAmui/kOi+k+eahOmHjeWkp+aUv+ayu+S7u+WKoeWSjOWktOetieaUu+WdmuW3peeoi+adpeaKk+OAguebruWJje+8jOS6pOmAmui/kOi+k+mDqOW3sue7j+WcqOmDqOWGheaIkOeri+S6hueUseS4u+imgemihuWvvOaMguW4heeahOS4k+mhueW3peS9nOaMh+aMpemDqO+8jOS4i+mdouiuvuS6hjnkuKrlt6XkvZzlsI/nu4TvvIznoJTnqbbliLblrprlhbfkvZPnmoTlt6XkvZzmlrnmoYjvvIznu4bljJblt6XkvZznm67moIfvvIzmmI7noa7lrp7mlr3nmoTot6/nur/lkoznm7jlhbPnmoTmioDmnK/mlrnmoYjjgIIK5ZC05pil6ICV6L+b5LiA5q2l5oyH5Ye677yM5pKk6ZSA6auY6YCf5YWs6Lev55yB55WM5pS26LS556uZ5piv5LiA5Liq5aSN5p2C55qE5bel56iL77yM5raJ5Y+K5Yiw5pS26LS55qih5byP55qE5pS56Z2p5Yib5paw77yM5Lmf5raJ5Y+K5Yiw5aSn6YeP55qE56Gs5Lu25bel56iL5bu66K6+5ZKM6L2v5Lu25Y2H57qn5pS56YCg77yM55u45YWz5pS/562W55qE57uf5LiA44CB5Lq65ZGY55qE5a6J572u562J77yM6Zq+5bqm5q+U6L6D5aSn77yM5Zuw6Zq+5Lmf5q+U6L6D5aSa44CCCuS7luW8uuiwg++8jOWwhuiwg+WKqOS4gOWIh+WKm+mHj++8jOaDs+WwveS4gOWIh+WKnuazle+8jOehruS/neS4pOW5tOWGheWKm+S6ieaPkOWJjeWfuuacrOWPlua2iOWFqOWbvemrmOmAn+WFrOi3r+ecgeeVjOaUtui0ueermeOAguWQjOaXtu+8jOaSpOmUgOaUtui0ueermeW3peS9nOeahOi/m+WxleS5n+S8muWPiuaXtuWQkeWkp+WutuWFrOWRiuOAguaXqeaXpeiuqeW5v+Wkp+S6uuawkee+pOS8l+S6q+WPl+WIsOaUuemdqeeahOe6ouWIqeOAggrotKPnvJbvvJrliJjoibPlkJs=
 	//
 	// 香港TVB拟与内地合拍综艺节目 头炮或是"港姐"选美--传媒--人民网
+	// March 30, 2019
 	// http://media.people.com.cn/n1/2019/0330/c40606-31004041.html
 	// 6aaZ5rivVFZC5ouf5LiO5YaF5Zyw5ZCI5ouN57u86Im66IqC55uuIOWktOeCruaIluaYryYjMzQ75riv5aeQJiMzNDvpgInnvo4K5o2u6aaZ5riv44CK5pif5bKb5pel5oql44CL5oql6YGT77yM6aaZ5rivVFZC6KGM5pS/5oC76KOB5p2O5a6d5a6J6L+R5pel6YCP6Zyy77yM5ouf5LiO5YaF5Zyw5ZCI5L2c5ouN5pGE57u86Im66IqC55uu77yM5aS054Ku5oiW5piv6ICB5pys6KGM55qE6YCJ576O6IqC55uu44CK6aaZ5riv5bCP5aeQ44CL562J77yM5YWs5Y+45Lmf6ICD6JmR5Zyo5YaF5Zyw6K6+56uL55S16KeG5Z+O44CCCjIwMTjlubTvvIxUVkLmlLblhaXlkIzmr5Tlop7plb8zOCXvvIzovr7liLAyNC405Lq/5riv5YWD77yM5aKe6ZW/5Yqo5Yqb5p2l6Ieq6IGU5ZCI5Yi25L2c55qE6L+e57ut5Ymn5Y+K572R5LiK6KeG6aKR77yM5YaF5Zyw5pS25YWl5Zug5q2k5aKe6ZW/MjYl44CC5p2O5a6d5a6J6K+077yM6L+R5bm05q+P6ZuG5ZCI5ouN5Ymn55qE5Y+r5Lu36LaK5p2l6LaK6auY77yM6aKE5pyf5LuK5bm05o6o5Ye6M+Wll+WQiOaLjeS9nOWTge+8jOacquadpeS8muWKoOW8uuWcqOWGheWcsOeahOWPkeWxleOAggrmnY7lrp3lronpgI/pnLLvvIzku4rlubTmi5/kuI7lhoXlnLDnvZHnu5zlubPlj7DlkIjkvZzmi43mkYTnu7zoibroioLnm67vvIznm67liY3miZPnrpfliLbkvZzku6XlvoDovoPmiJDlip/nmoTkvZzlk4HjgILkvovlpoLjgIrpppnmuK/lsI/lp5DjgIvjgIHjgIrlm73pmYXkuK3ljY7lsI/lp5DjgIvvvIzlh63mpI3lhaXlvI/lub/lkYrluKbliqjnlLXllYbkuJrliqHvvIzkuZ/kuI3mjpLpmaTlho3mt7vpgInnvo7oioLnm67vvIzkvYbnm67liY3mnKrmnInlrprmoYjjgIIK5p2O5a6d5a6J6K+077yM5Lul5b6A6aaZ5riv55qE55uu5qCH6KeC5LyX6L6D5bCR77yM5pel5ZCO6Z2i5a+55YaF5Zyw6KeC5LyX77yM5Y+v5aSn5aSn5aKe5Yqg6aKE566X44CC5a+55LqO5YaF5Zyw5Y+R5bGV55qE6ZW/6L+c5aSn6K6h77yM5LuW6K+077yMVFZC6K6h5YiS5Zyo57Kk5riv5r6z5aSn5rm+5Yy65YW05bu65Y6C5oi/77yM6Zmk5LqG5L2c5ouN5pGE5Zy65Zyw5aSW77yM5Lmf6ICD6JmR55So5p2l5oub6IGY6Im65ZGY5L2c5Li66K6t57uD5Zy65omA562J77yM5YW26YGT5YW35Y+K5Zy65pmv6YOo5YiG5Lia5Yqh5oiW5Y+v5oiQ56uL5pyJ6KeE5qih55qE5YWs5Y+444CC77yI6ZKfIOaso++8iQoo6LSj57yW77ya5a6L5b+D6JWK44CB6LW15YWJ6ZyeKQ==
 
@@ -136,3 +139,217 @@ func TestStartsWithNumber(t *testing.T) {
 		t.Error("not expected to start with number")
 	}
 }
+
+func TestDocumentTitleMatchClassifierParts(t *testing.T) {
+	// The headline is shorter than the site name, so it's only a candidate
+	// since it's a part of the title with at least 4 words
+	doc, err := ParseDocument(strings.NewReader(`<html>
+<head><title>Council approves new park | The Example Daily News and Information Online Edition</title></head>
+<body>
+<h1>Council approves new park</h1>
+<p>The city council approved a new park on Tuesday after a long debate about the site of the old rail yard.</p>
+</body>
+</html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	DocumentTitleMatchClassifier().Process(doc)
+	if tb := doc.TextBlocks[0]; !tb.HasLabel(LabelTitle) {
+		t.Errorf("expected '%s' to be labeled as the title", tb.Text)
+	}
+	if exp := "Council approves new park"; doc.CleanTitle != exp {
+		t.Errorf("expected clean title '%s' but got '%s'", exp, doc.CleanTitle)
+	}
+}
+
+const headingTitleTestHTML = `<html>
+<head><title>React App</title></head>
+<body>
+<div class="nav"><h1><a href="/">Example News</a></h1></div>
+<h2>Most popular</h2>
+<article>
+<h1>The headline of the story</h1>
+<p>This is the first paragraph of the story, which has enough words to be classified as content by the classifier.</p>
+<p>This is the second paragraph of the story, which also has enough words to be classified as content by the classifier.</p>
+</article>
+</body>
+</html>`
+
+func TestHeadingTitleClassifier(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(headingTitleTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipeline := &Pipeline{
+		PipelineName: "HeadingTitle",
+		Filters: []Filter{
+			DocumentTitleMatchClassifier(),
+			NumWordsRulesClassifier(),
+			HeadingTitleClassifier(),
+		},
+	}
+	pipeline.Process(doc)
+
+	exp := "The headline of the story"
+	if doc.Title != exp {
+		t.Errorf("expected title '%s' but got '%s'", exp, doc.Title)
+	}
+
+	numTitles := 0
+	for _, tb := range doc.TextBlocks {
+		if tb.HasLabel(LabelTitle) {
+			numTitles++
+			if tb.Text != exp {
+				t.Errorf("expected title block '%s' but got '%s'", exp, tb.Text)
+			}
+		}
+	}
+	if numTitles != 1 {
+		t.Errorf("expected 1 title block but got %d", numTitles)
+	}
+
+	// ArticlePipeline keeps the title of the document
+	doc, err = ParseDocument(strings.NewReader(headingTitleTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ArticlePipeline.Process(doc)
+	if exp := "React App"; doc.Title != exp {
+		t.Errorf("expected title '%s' but got '%s'", exp, doc.Title)
+	}
+	doc, err = ParseDocument(strings.NewReader(headingTitleTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ExtendedArticlePipeline.Process(doc)
+	if doc.Title != exp {
+		t.Errorf("expected title '%s' but got '%s'", exp, doc.Title)
+	}
+
+	// A specific title is kept
+	doc, err = ParseDocument(strings.NewReader(strings.Replace(headingTitleTestHTML, "React App", "A specific title", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HeadingTitleClassifier().Process(doc) {
+		t.Error("expected a specific title to not change")
+	}
+}
+
+func TestExtendedArticlePipeline(t *testing.T) {
+	hasFilter := func(pipeline *Pipeline, name string) bool {
+		for _, filter := range pipeline.Filters {
+			if filter.Name() == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The filters which change the content are opt-in
+	for _, name := range []string{"HeadingTitleClassifier", "BylineToBoilerplate"} {
+		if hasFilter(ArticlePipeline, name) {
+			t.Errorf("expected %s not to be in %s", name, ArticlePipeline.Name())
+		}
+		if !hasFilter(ExtendedArticlePipeline, name) {
+			t.Errorf("expected %s to be in %s", name, ExtendedArticlePipeline.Name())
+		}
+	}
+	for _, filter := range ArticlePipeline.Filters {
+		if !hasFilter(ExtendedArticlePipeline, filter.Name()) {
+			t.Errorf("expected %s to be in %s", filter.Name(), ExtendedArticlePipeline.Name())
+		}
+	}
+}
+
+// changeFilter is a filter which reports whether it changed the document.
+type changeFilter struct {
+	name    string
+	changed bool
+}
+
+func (f changeFilter) Name() string { return f.name }
+
+func (f changeFilter) Process(doc *Document) bool { return f.changed }
+
+func TestPipelineHooks(t *testing.T) {
+	var calls []string
+	hook := func(id string) Hook {
+		return Hook{
+			BeforeFilter: func(name string, doc *Document) {
+				calls = append(calls, fmt.Sprintf("%s before %s", id, name))
+			},
+			AfterFilter: func(name string, doc *Document, hasChanged bool) {
+				calls = append(calls, fmt.Sprintf("%s after %s %t", id, name, hasChanged))
+			},
+		}
+	}
+
+	pipeline := &Pipeline{
+		PipelineName: "Test",
+		Filters: []Filter{
+			changeFilter{"A", true},
+			&Pipeline{
+				PipelineName: "Nested",
+				Filters:      []Filter{changeFilter{"B", false}},
+				Hooks:        []Hook{{AfterFilter: hook("nested").AfterFilter}},
+			},
+		},
+		Hooks: []Hook{hook("1"), hook("2")},
+	}
+
+	exp := []string{
+		"1 before A",
+		"2 before A",
+		"1 after A true",
+		"2 after A true",
+		"1 before Nested",
+		"2 before Nested",
+		"nested after B false",
+		"1 after Nested false",
+		"2 after Nested false",
+	}
+
+	if !pipeline.Process(&Document{}) {
+		t.Error("expected document to be changed")
+	}
+	if strings.Join(calls, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("expected calls %q but got %q", exp, calls)
+	}
+
+	calls = nil
+	if _, err := pipeline.ProcessContext(context.Background(), &Document{}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(calls, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("expected context calls %q but got %q", exp, calls)
+	}
+}
+
+func TestPipelineHooksCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var processed int
+	var after []string
+	pipeline := &Pipeline{
+		PipelineName: "Test",
+		Filters: []Filter{
+			cancelFilter{cancel: cancel, processed: &processed},
+			changeFilter{"A", true},
+		},
+		Hooks: []Hook{{
+			AfterFilter: func(name string, doc *Document, hasChanged bool) {
+				after = append(after, name)
+			},
+		}},
+	}
+
+	if _, err := pipeline.ProcessContext(ctx, &Document{}); err == nil {
+		t.Error("expected canceled error")
+	}
+	if exp := []string{"Cancel"}; strings.Join(after, ",") != strings.Join(exp, ",") {
+		t.Errorf("expected hooks after %v but got %v", exp, after)
+	}
+}
@@ -0,0 +1,102 @@
+package boilerpipe
+
+import (
+	"encoding/json"
+)
+
+// textBlock and document have the fields of TextBlock and Document without
+// their JSON methods.
+type (
+	textBlock TextBlock
+	document  Document
+)
+
+// jsonTextBlock is the JSON encoding of a TextBlock, which includes the
+// count of each of its labels by name.
+type jsonTextBlock struct {
+	*textBlock
+	Labels map[Label]int `json:"labels"`
+}
+
+// MarshalJSON encodes the text block along with its labels.
+func (tb *TextBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonTextBlock{
+		textBlock: (*textBlock)(tb),
+		Labels:    tb.labelMap,
+	})
+}
+
+// UnmarshalJSON decodes a text block encoded by MarshalJSON.
+func (tb *TextBlock) UnmarshalJSON(b []byte) error {
+	v := &jsonTextBlock{textBlock: (*textBlock)(tb)}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	tb.labelMap = v.Labels
+	if tb.labelMap == nil {
+		tb.labelMap = make(map[Label]int)
+	}
+	return nil
+}
+
+// jsonDocument is the JSON encoding of a Document, which includes the state
+// used to resolve its URLs again and the text block offsets of its tables.
+type jsonDocument struct {
+	*document
+	Tables []*jsonTable `json:"tables"`
+
+	BaseHref      string         `json:"baseHref,omitempty"`
+	CanonicalRefs []string       `json:"canonicalRefs,omitempty"`
+	NextPageRefs  []string       `json:"nextPageRefs,omitempty"`
+	PageRefs      map[int]string `json:"pageRefs,omitempty"`
+}
+
+type jsonTable struct {
+	*Table
+	OffsetStart int `json:"offsetBlocksStart"`
+	OffsetEnd   int `json:"offsetBlocksEnd"`
+}
+
+// MarshalJSON encodes the document, including the labels of its text blocks,
+// so it can be decoded with UnmarshalJSON and processed by filters again.
+func (doc *Document) MarshalJSON() ([]byte, error) {
+	v := &jsonDocument{
+		document:      (*document)(doc),
+		Tables:        make([]*jsonTable, 0, len(doc.Tables)),
+		BaseHref:      doc.baseHref,
+		CanonicalRefs: doc.canonicalRefs,
+		NextPageRefs:  doc.nextPageRefs,
+		PageRefs:      doc.pageRefs,
+	}
+	for _, t := range doc.Tables {
+		v.Tables = append(v.Tables, &jsonTable{
+			Table:       t,
+			OffsetStart: t.offsetStart,
+			OffsetEnd:   t.offsetEnd,
+		})
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a document encoded by MarshalJSON.
+func (doc *Document) UnmarshalJSON(b []byte) error {
+	v := &jsonDocument{document: (*document)(doc)}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+
+	doc.Tables = nil
+	for _, t := range v.Tables {
+		if t == nil || t.Table == nil {
+			continue
+		}
+		t.Table.offsetStart = t.OffsetStart
+		t.Table.offsetEnd = t.OffsetEnd
+		doc.Tables = append(doc.Tables, t.Table)
+	}
+	doc.baseHref = v.BaseHref
+	doc.canonicalRefs = v.CanonicalRefs
+	doc.nextPageRefs = v.NextPageRefs
+	doc.pageRefs = v.PageRefs
+	return nil
+}
@@ -0,0 +1,109 @@
+package boilerpipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+)
+
+func TestParseLabel(t *testing.T) {
+	for label := LabelIndicatesEndOfText; label <= LabelComment; label++ {
+		act, err := ParseLabel(label.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act != label {
+			t.Errorf("expected label %s but got %s", label, act)
+		}
+	}
+
+	if _, err := ParseLabel("LabelUnknown"); err == nil {
+		t.Error("expected error for unknown label")
+	}
+	if _, err := Label(-1).MarshalText(); err == nil {
+		t.Error("expected error for invalid label")
+	}
+}
+
+func TestTextBlockJSON(t *testing.T) {
+	tb := NewTextBlock()
+	tb.Text = "The text of the block."
+	tb.NumWords = 5
+	tb.NumLinkedWords = 2
+	tb.TagLevel = 3
+	tb.IsContent = true
+	tb.AddLabels(LabelTitle, LabelHeading, LabelHeading)
+
+	b, err := json.Marshal(tb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `"labels":{"LabelHeading":2,"LabelTitle":1}`; !strings.Contains(string(b), exp) {
+		t.Errorf("expected JSON to contain '%s' but got '%s'", exp, b)
+	}
+
+	var act TextBlock
+	if err := json.Unmarshal(b, &act); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&act, tb) {
+		t.Errorf("expected text block %+v but got %+v", tb, &act)
+	}
+}
+
+func TestDocumentJSON(t *testing.T) {
+	for i := 0; i < 7; i++ {
+		f, err := os.Open(filepath.Join("testdata", fmt.Sprintf("%d.html", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := ParseDocument(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := normurl.Parse("https://example.com/2019/03/28/article")
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.SetURL(u)
+
+		// Save the document halfway through the pipeline
+		half := len(ArticlePipeline.Filters) / 2
+		first := &Pipeline{PipelineName: "First", Filters: ArticlePipeline.Filters[:half]}
+		second := &Pipeline{PipelineName: "Second", Filters: ArticlePipeline.Filters[half:]}
+		first.Process(doc)
+
+		b, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var replayed Document
+		if err := json.Unmarshal(b, &replayed); err != nil {
+			t.Fatal(err)
+		}
+		b2, err := json.Marshal(&replayed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b2) != string(b) {
+			t.Errorf("%d: expected decoded document to encode as '%s' but got '%s'", i, b, b2)
+		}
+
+		// Replay the rest of the pipeline on both documents
+		second.Process(doc)
+		second.Process(&replayed)
+		if act, exp := replayed.Content(), doc.Content(); act != exp {
+			t.Errorf("%d: expected content '%s' but got '%s'", i, exp, act)
+		}
+		if act, exp := len(replayed.ContentTables()), len(doc.ContentTables()); act != exp {
+			t.Errorf("%d: expected %d content tables but got %d", i, exp, act)
+		}
+	}
+}
@@ -4,9 +4,9 @@ package boilerpipe
 
 import "strconv"
 
-const _Label_name = "LabelIndicatesEndOfTextLabelMightBeContentLabelVeryLikelyContentLabelTitleLabelListLabelHeadingLabelHeading1LabelHeading2LabelHeading3"
+const _Label_name = "LabelIndicatesEndOfTextLabelMightBeContentLabelVeryLikelyContentLabelTitleLabelListLabelHeadingLabelHeading1LabelHeading2LabelHeading3LabelBylineLabelComment"
 
-var _Label_index = [...]uint8{0, 23, 42, 64, 74, 83, 95, 108, 121, 134}
+var _Label_index = [...]uint8{0, 23, 42, 64, 74, 83, 95, 108, 121, 134, 145, 157}
 
 func (i Label) String() string {
 	if i < 0 || i >= Label(len(_Label_index)-1) {
@@ -0,0 +1,384 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// languageProfile contains the language-specific heuristics used when
+// detecting the language of a document and processing its text blocks.
+type languageProfile struct {
+	// stopWords are the most common words of the language.
+	stopWords map[string]bool
+
+	// terminatingPhrases are the lowercase phrases which indicate the end
+	// of the text content (e.g. the start of the comments).
+	terminatingPhrases []string
+
+	// tokenize splits text into tokens for SplitWords, or nil if the
+	// default tokenizer is used.
+	tokenize func(b *bytes.Buffer) []string
+}
+
+func newStopWords(words string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		m[w] = true
+	}
+	return m
+}
+
+var languageProfiles = map[string]*languageProfile{
+	"en": {
+		stopWords: newStopWords("the of and to in is that for it was on with as he be at by this had not are but from or have an they which you were her she there been has their we his will would its who said"),
+	},
+	"de": {
+		stopWords:          newStopWords("der die und in den von zu das mit sich des auf für ist im dem nicht ein eine als auch es an werden aus er hat dass sie nach wird bei einer um am sind noch wie einem über"),
+		terminatingPhrases: []string{"kommentare", "leserkommentare", "diskutieren sie mit", "kommentar schreiben"},
+	},
+	"fr": {
+		stopWords:          newStopWords("le de la et les des en un du une que est pour qui dans par plus pas au sur ne se sont il ce avec elle ont été aux cette mais ou son sa ses"),
+		terminatingPhrases: []string{"commentaires", "réagissez", "laisser un commentaire", "ajouter un commentaire"},
+	},
+	"es": {
+		stopWords:          newStopWords("de la que el en y a los del se las por un para con no una su al es lo como más pero sus le ya o este fue ha sí porque esta entre"),
+		terminatingPhrases: []string{"comentarios", "deja un comentario", "escribe un comentario"},
+	},
+	"it": {
+		stopWords:          newStopWords("di e il la che è per un in una sono del della non si le con da al dei nel alla gli anche ha come ma più lo ci questo delle"),
+		terminatingPhrases: []string{"commenti", "lascia un commento", "scrivi un commento"},
+	},
+	"pt": {
+		stopWords:          newStopWords("de a o que e do da em um para é com não uma os no se na por mais as dos como mas foi ao ele das tem à seu sua ou ser"),
+		terminatingPhrases: []string{"comentários", "deixe um comentário", "deixe seu comentário"},
+	},
+	"nl": {
+		stopWords:          newStopWords("de en van het een in is dat op te zijn met voor niet aan er om ook als dan maar bij of uit nog worden door naar heeft wordt deze"),
+		terminatingPhrases: []string{"reacties", "plaats een reactie", "reageer"},
+	},
+	"sv": {
+		stopWords:          newStopWords("och i att det som en på är av för med till den har de inte om ett han men var jag sig från vi så kan man när år också efter"),
+		terminatingPhrases: []string{"kommentarer", "skriv en kommentar"},
+	},
+	"zh": {
+		terminatingPhrases: []string{"相关新闻", "相关阅读", "网友评论", "发表评论", "热门推荐", "延伸阅读"},
+		tokenize:           tokenizeCJK,
+	},
+	"ja": {
+		terminatingPhrases: []string{"関連記事", "コメントを書く", "コメント一覧"},
+		tokenize:           tokenizeCJK,
+	},
+	"ko": {
+		terminatingPhrases: []string{"관련기사", "댓글 달기", "댓글 보기"},
+	},
+}
+
+// SetLanguage sets the declared language of the document. Invalid language
+// tags are ignored.
+func (h *contentHandler) SetLanguage(tag string) {
+	if lang := normalizeLanguage(tag); lang != "" {
+		h.lang = lang
+	}
+}
+
+// isTerminatingPhrase returns true if the lowercase text starts with one of
+// the terminating phrases of the language.
+func isTerminatingPhrase(lang, textLC string) bool {
+	p := languageProfiles[lang]
+	if p == nil {
+		return false
+	}
+	for _, phrase := range p.terminatingPhrases {
+		if strings.HasPrefix(textLC, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLanguage returns the lowercase primary subtag of a BCP 47 language
+// tag (e.g. "en" for "en-US" or "en_us"), or an empty string if the tag is
+// not valid.
+func normalizeLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return ""
+	}
+
+	isAlpha := func(s string) bool {
+		for _, r := range s {
+			if r < 'a' || r > 'z' {
+				return false
+			}
+		}
+		return true
+	}
+
+	primary := parts[0]
+	if len(primary) < 2 || len(primary) > 3 || !isAlpha(primary) {
+		return ""
+	}
+
+	// Subtags must be between 2 and 8 characters (e.g. not "utf-8")
+	for _, p := range parts[1:] {
+		if len(p) < 2 || len(p) > 8 {
+			return ""
+		}
+	}
+
+	return primary
+}
+
+// scriptLanguages are the languages detected by the script of their
+// letters, in order of precedence.
+var scriptLanguages = []struct {
+	lang   string
+	tables []*unicode.RangeTable
+}{
+	{"ja", []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana}},
+	{"ko", []*unicode.RangeTable{unicode.Hangul}},
+	{"zh", []*unicode.RangeTable{unicode.Han}},
+	{"ru", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"ar", []*unicode.RangeTable{unicode.Arabic}},
+	{"el", []*unicode.RangeTable{unicode.Greek}},
+	{"he", []*unicode.RangeTable{unicode.Hebrew}},
+	{"th", []*unicode.RangeTable{unicode.Thai}},
+	{"hi", []*unicode.RangeTable{unicode.Devanagari}},
+}
+
+// minScriptRatio is the minimum ratio of letters in a script for the text to
+// be detected as a language of that script. Japanese is detected with a lower
+// ratio since it's usually mixed with Han characters.
+const (
+	minScriptRatio         = 0.3
+	minJapaneseScriptRatio = 0.1
+)
+
+// DetectLanguage returns the language of the text as a lowercase ISO 639-1
+// code, or an empty string if it can't be detected. Languages written in
+// their own script are detected by the script of the letters, and languages
+// written in the Latin script by the frequency of their stop words.
+func DetectLanguage(text string) string {
+	var (
+		numLetters int
+		numScript  = make([]int, len(scriptLanguages))
+	)
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		numLetters++
+		for i, sl := range scriptLanguages {
+			if unicode.IsOneOf(sl.tables, r) {
+				numScript[i]++
+				break
+			}
+		}
+	}
+
+	if numLetters == 0 {
+		return ""
+	}
+
+	for i, sl := range scriptLanguages {
+		minRatio := minScriptRatio
+		if sl.lang == "ja" {
+			minRatio = minJapaneseScriptRatio
+		}
+		if float64(numScript[i])/float64(numLetters) >= minRatio {
+			return refineScriptLanguage(sl.lang, text)
+		}
+	}
+
+	return detectStopWordLanguage(text)
+}
+
+// refineScriptLanguage distinguishes languages which share a script by their
+// unique letters.
+func refineScriptLanguage(lang, text string) string {
+	switch lang {
+	case "ru":
+		if strings.ContainsAny(text, "їєґі") {
+			return "uk"
+		}
+	case "ar":
+		if strings.ContainsAny(text, "پچژگ") {
+			return "fa"
+		}
+	}
+	return lang
+}
+
+// minStopWords is the minimum number of stop words for a language to be
+// detected.
+const minStopWords = 3
+
+func detectStopWordLanguage(text string) string {
+	counts := make(map[string]int)
+
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		for lang, profile := range languageProfiles {
+			if profile.stopWords[w] {
+				counts[lang]++
+			}
+		}
+	}
+
+	best, bestCount := "", minStopWords-1
+	for lang, count := range counts {
+		// Break ties by language code so detection is deterministic
+		if count > bestCount || count == bestCount && best != "" && lang < best {
+			best, bestCount = lang, count
+		}
+	}
+	return best
+}
+
+// maxLanguageDetectionBytes is the maximum amount of text used to detect the
+// language of a document.
+const maxLanguageDetectionBytes = 16 * 1024
+
+// detectDocumentLanguage detects the language of the text blocks, using the
+// blocks with the most words first since they're most likely content.
+func detectDocumentLanguage(textBlocks []*TextBlock) string {
+	var blocks []*TextBlock
+	for _, tb := range textBlocks {
+		if tb.NumWords >= 10 && tb.LinkDensity() <= 0.5 {
+			blocks = append(blocks, tb)
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].NumWords > blocks[j].NumWords
+	})
+
+	buf := &bytes.Buffer{}
+	for _, tb := range blocks {
+		buf.WriteString(tb.Text)
+		buf.WriteByte('\n')
+		if buf.Len() >= maxLanguageDetectionBytes {
+			break
+		}
+	}
+	if buf.Len() == 0 {
+		for _, tb := range textBlocks {
+			buf.WriteString(tb.Text)
+			buf.WriteByte('\n')
+		}
+	}
+	return DetectLanguage(buf.String())
+}
+
+// resolveLanguage returns the declared language, unless the detected language
+// is written in a different script in which case the declaration is likely
+// wrong (e.g. a template with lang="en" on a Chinese page).
+func resolveLanguage(declared, detected string) string {
+	if declared == "" {
+		return detected
+	}
+	if detected == "" || languageScript(declared) == languageScript(detected) {
+		return declared
+	}
+	return detected
+}
+
+// languageScript returns the script a language is detected by, or "Latin".
+func languageScript(lang string) string {
+	switch lang {
+	case "uk":
+		lang = "ru"
+	case "fa":
+		lang = "ar"
+	}
+	for _, sl := range scriptLanguages {
+		if sl.lang == lang {
+			return sl.lang
+		}
+	}
+	return "Latin"
+}
+
+// isCJK returns true if the rune is written without spaces between words.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isCJKText returns true if at least minScriptRatio of the letters of the
+// text are Chinese or Japanese characters.
+func isCJKText(text string) bool {
+	var numLetters, numCJK int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		numLetters++
+		if isCJK(r) {
+			numCJK++
+		}
+	}
+	return numLetters > 0 && float64(numCJK)/float64(numLetters) >= minScriptRatio
+}
+
+// SplitWords splits text into words with the tokenizer of the language (e.g.
+// the Language of a Document). Text mostly written in Chinese or Japanese is
+// split into words whatever the language, since the declared language of a
+// document is often missing or wrong.
+func SplitWords(text, lang string) (words []string) {
+	tokenize := tokenize
+	if p := languageProfiles[lang]; p != nil && p.tokenize != nil {
+		tokenize = p.tokenize
+	}
+	if isCJKText(text) {
+		tokenize = tokenizeCJK
+	}
+
+	for _, tok := range tokenize(bytes.NewBufferString(text)) {
+		if isWord(tok) {
+			words = append(words, tok)
+		}
+	}
+	return
+}
+
+// cjkWordLength is the average number of characters in a Chinese or Japanese
+// word.
+const cjkWordLength = 2
+
+// tokenizeCJK tokenizes text like tokenize, but splits runs of Chinese and
+// Japanese characters into tokens of cjkWordLength characters since words are
+// not separated by spaces.
+func tokenizeCJK(b *bytes.Buffer) []string {
+	tokens := tokenize(b)
+	split := make([]string, 0, len(tokens))
+
+	for _, tok := range tokens {
+		if strings.IndexFunc(tok, isCJK) == -1 {
+			split = append(split, tok)
+			continue
+		}
+
+		var (
+			start  = 0
+			numCJK = 0
+			inCJK  = false
+		)
+		for i, r := range tok {
+			cjk := isCJK(r)
+			if i > start && (cjk != inCJK || cjk && numCJK == cjkWordLength) {
+				split = append(split, tok[start:i])
+				start, numCJK = i, 0
+			}
+			if cjk {
+				numCJK++
+			}
+			inCJK = cjk
+		}
+		split = append(split, tok[start:])
+	}
+	return split
+}
@@ -0,0 +1,163 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var normalizeLanguageTestData = map[string]string{
+	"en":         "en",
+	"en-US":      "en",
+	"en_us":      "en",
+	" ZH-Hans ":  "zh",
+	"zh-Hant-TW": "zh",
+	"utf-8":      "",
+	"english":    "",
+	"":           "",
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for tag, exp := range normalizeLanguageTestData {
+		if act := normalizeLanguage(tag); act != exp {
+			t.Errorf("%q: expected '%s' but got '%s'", tag, exp, act)
+		}
+	}
+}
+
+var detectLanguageTestData = map[string]string{
+	"The quick brown fox jumps over the lazy dog and it was said that he is at the park.":    "en",
+	"Der schnelle braune Fuchs springt über den faulen Hund, und er ist nicht mit dem Hund.": "de",
+	"Le renard brun rapide saute par-dessus le chien paresseux et il est dans la maison.":    "fr",
+	"El rápido zorro marrón salta sobre el perro perezoso y los niños de la casa.":           "es",
+	"交通运输部新闻发言人吴春耕今日在谈及此工作最新进展时表示":                                                           "zh",
+	"東京都は今日、新しい計画を発表しました。":                                                                   "ja",
+	"서울에서 새로운 계획이 발표되었습니다":                                                                   "ko",
+	"Быстрая коричневая лиса прыгает через ленивую собаку":                                   "ru",
+	"12345 !!!": "",
+	"Lorem":     "",
+}
+
+func TestDetectLanguage(t *testing.T) {
+	for text, exp := range detectLanguageTestData {
+		if act := DetectLanguage(text); act != exp {
+			t.Errorf("%s: expected '%s' but got '%s'", text, exp, act)
+		}
+	}
+}
+
+func TestResolveLanguage(t *testing.T) {
+	tests := []struct {
+		declared, detected, exp string
+	}{
+		{"en", "en", "en"},
+		{"en", "de", "en"},
+		{"en", "zh", "zh"},
+		{"zh", "ja", "ja"},
+		{"ru", "uk", "ru"},
+		{"", "fr", "fr"},
+		{"fr", "", "fr"},
+	}
+	for _, test := range tests {
+		if act := resolveLanguage(test.declared, test.detected); act != test.exp {
+			t.Errorf("%s/%s: expected '%s' but got '%s'", test.declared, test.detected, test.exp, act)
+		}
+	}
+}
+
+func TestTokenizeCJK(t *testing.T) {
+	exp := []string{"今年", "政府", "工作", "报告", "提出", "两年", "内", "TVB", "拟与", "内地"}
+	act := tokenizeCJK(bytes.NewBufferString("今年政府工作报告提出两年内TVB拟与内地"))
+	if strings.Join(act, " ") != strings.Join(exp, " ") {
+		t.Errorf("expected %v but got %v", exp, act)
+	}
+}
+
+var documentLanguageTestData = map[string]string{
+	`<html lang="de-DE"><body><p>Hello</p></body></html>`:                                                                                        "de",
+	`<html><head><meta http-equiv="content-language" content="fr"></head><body><p>Bonjour</p></body></html>`:                                     "fr",
+	`<html><head><meta http-equiv="content-language" content="utf-8"></head><body><p>Hola</p></body></html>`:                                     "",
+	`<html><head><script type="application/ld+json">{"@type":"NewsArticle","inLanguage":"es-ES"}</script></head><body><p>Hola</p></body></html>`: "es",
+	`<html lang="en"><body><p>交通运输部新闻发言人吴春耕今日在谈及此工作最新进展时表示，交通运输部已成立专项工作指挥部。</p></body></html>`:                                                   "zh",
+	`<html><body><p>This is the text of the article, and it was written in English by the author.</p></body></html>`:                             "en",
+}
+
+func TestDocumentLanguage(t *testing.T) {
+	for s, exp := range documentLanguageTestData {
+		doc, err := ParseDocument(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.Language != exp {
+			t.Errorf("%s: expected '%s' but got '%s'", s, exp, doc.Language)
+		}
+	}
+}
+
+func TestDetectDocumentLanguageLargestBlocks(t *testing.T) {
+	// The short blocks fill the text used for detection unless the largest
+	// block is used first
+	var textBlocks []*TextBlock
+	en := "This is a short paragraph of English text with some words."
+	for i := 0; i < maxLanguageDetectionBytes/len(en)+1; i++ {
+		textBlocks = append(textBlocks, &TextBlock{Text: en, NumWords: 11})
+	}
+	zh := strings.Repeat("这是一个很长的中文段落，其中有很多的字和词语。", maxLanguageDetectionBytes/60+1)
+	textBlocks = append(textBlocks, &TextBlock{Text: zh, NumWords: 1000})
+
+	if act, exp := detectDocumentLanguage(textBlocks), "zh"; act != exp {
+		t.Errorf("expected '%s' but got '%s'", exp, act)
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		text, lang string
+		exp        []string
+	}{
+		{"The quick brown fox jumps - twice.", "en", []string{"The", "quick", "brown", "fox", "jumps", "twice."}},
+		{"今年政府工作报告提出，两年内", "zh", []string{"今年", "政府", "工作", "报告", "提出", "两年", "内"}},
+		{"今年政府工作报告提出，两年内", "en", []string{"今年", "政府", "工作", "报告", "提出", "两年", "内"}},
+		{"今年政府工作报告提出，两年内", "", []string{"今年", "政府", "工作", "报告", "提出", "两年", "内"}},
+	}
+	for _, test := range tests {
+		if act := SplitWords(test.text, test.lang); strings.Join(act, " ") != strings.Join(test.exp, " ") {
+			t.Errorf("%s/%s: expected %v but got %v", test.text, test.lang, test.exp, act)
+		}
+	}
+}
+
+func TestSplitWordsDeclaredLanguage(t *testing.T) {
+	const text = "交通运输部新闻发言人吴春耕今日在谈及此工作最新进展时表示，交通运输部已成立专项工作指挥部，全力推进取消高速公路省界收费站工作。"
+
+	var expWords, expNumWords int
+	for i, html := range []string{
+		`<html lang="zh"><body><p>` + text + `</p></body></html>`,
+		`<html><body><p>` + text + `</p></body></html>`,
+		`<html lang="en"><body><p>` + text + `</p></body></html>`,
+	} {
+		doc, err := ParseDocument(strings.NewReader(html))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(doc.TextBlocks) != 1 {
+			t.Fatalf("%s: expected 1 text block but got %d", html, len(doc.TextBlocks))
+		}
+		tb := doc.TextBlocks[0]
+		words := len(SplitWords(tb.Text, doc.Language))
+
+		if i == 0 {
+			expWords, expNumWords = words, tb.NumWords
+			if expWords != 31 {
+				t.Errorf("%s: expected 31 words but got %d", html, expWords)
+			}
+			continue
+		}
+		if words != expWords {
+			t.Errorf("%s: expected %d words but got %d", html, expWords, words)
+		}
+		if tb.NumWords != expNumWords {
+			t.Errorf("%s: expected %d classified words but got %d", html, expNumWords, tb.NumWords)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package boilerpipe
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Metadata is the document metadata found in <meta> tags, including the
+// OpenGraph (og:*, article:*), Twitter Card (twitter:*) and standard
+// (author, description, keywords) properties.
+type Metadata struct {
+	// Title is the og:title, or else the twitter:title.
+	Title string `json:"title"`
+
+	// Description is the og:description, or else the twitter:description,
+	// or else the standard description.
+	Description string `json:"description"`
+
+	// SiteName is the og:site_name.
+	SiteName string `json:"siteName"`
+
+	// Image is the og:image, or else the twitter:image.
+	Image string `json:"image"`
+
+	// URL is the og:url.
+	URL string `json:"url"`
+
+	// Type is the og:type.
+	Type string `json:"type"`
+
+	// Section is the article:section.
+	Section string `json:"section"`
+
+	// Language is the http-equiv content-language, or else the og:locale,
+	// or else the standard language.
+	Language string `json:"language"`
+
+	// Authors are the article:author values followed by the standard author
+	// values.
+	Authors []string `json:"authors"`
+
+	// Keywords are the standard keywords followed by the article:tag values.
+	Keywords []string `json:"keywords"`
+
+	// DatePublished is the article:published_time, or else the first of the
+	// other common published date names.
+	DatePublished time.Time `json:"datePublished"`
+
+	// DateModified is the article:modified_time, or else the
+	// og:updated_time, or else the first of the other common modified date
+	// names.
+	DateModified time.Time `json:"dateModified"`
+
+	TwitterCard    string `json:"twitterCard"`
+	TwitterSite    string `json:"twitterSite"`
+	TwitterCreator string `json:"twitterCreator"`
+
+	// Properties contains every <meta> name or property, lowercased, mapped
+	// to its values in document order.
+	Properties map[string][]string `json:"properties"`
+}
+
+// Get returns the first value of the given meta name or property, or an
+// empty string if it does not exist.
+func (m *Metadata) Get(key string) string {
+	if values := m.Properties[strings.ToLower(key)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// first returns the first non-empty value of the given keys.
+func (m *Metadata) first(keys ...string) string {
+	for _, key := range keys {
+		for _, v := range m.Properties[key] {
+			if v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// all returns all non-empty values of the given keys.
+func (m *Metadata) all(keys ...string) (values []string) {
+	for _, key := range keys {
+		for _, v := range m.Properties[key] {
+			if v != "" {
+				values = appendUnique(values, v)
+			}
+		}
+	}
+	return
+}
+
+func newMetadata(properties map[string][]string) *Metadata {
+	m := &Metadata{
+		Properties: properties,
+	}
+
+	m.Title = m.first("og:title", "twitter:title")
+	m.Description = m.first("og:description", "twitter:description", "description")
+	m.SiteName = m.first("og:site_name")
+	m.Image = m.first("og:image", "og:image:url", "og:image:secure_url", "twitter:image", "twitter:image:src")
+	m.URL = m.first("og:url")
+	m.Type = m.first("og:type")
+	m.Section = m.first("article:section")
+	m.Language = m.first("content-language", "og:locale", "language", "dc.language")
+	m.Authors = m.all("article:author", "author")
+
+	m.Keywords = splitKeywords(m.all("keywords"))
+	for _, v := range m.all("article:tag") {
+		m.Keywords = appendUnique(m.Keywords, v)
+	}
+
+	m.DatePublished = bestDate(metaDateCandidates(m), DateKindPublished, time.Time{})
+	m.DateModified = bestDate(metaDateCandidates(m), DateKindModified, time.Time{})
+
+	m.TwitterCard = m.first("twitter:card")
+	m.TwitterSite = m.first("twitter:site")
+	m.TwitterCreator = m.first("twitter:creator")
+
+	return m
+}
+
+// MetaElement collects the name, property or http-equiv and content of a
+// <meta> tag.
+func (h *contentHandler) MetaElement(tok *html.Token) {
+	var key, content string
+	hasContent := false
+
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "property", "name", "http-equiv":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = attr.Val
+			hasContent = true
+		case "value":
+			if !hasContent {
+				content = attr.Val
+			}
+		}
+	}
+
+	if key == "" {
+		return
+	}
+
+	h.metadata[key] = append(h.metadata[key], strings.TrimSpace(content))
+
+	if key == "content-language" && h.lang == "" {
+		h.SetLanguage(content)
+	}
+}
+
+func appendUnique(values []string, v string) []string {
+	for _, existing := range values {
+		if existing == v {
+			return values
+		}
+	}
+	return append(values, v)
+}
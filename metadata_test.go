@@ -0,0 +1,73 @@
+package boilerpipe
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const metadataTestHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta property="og:title" content="Lease: No rent for Raiders" />
+<meta property="og:description" content="The Raiders would not pay rent.">
+<meta property="og:site_name" content="LasVegasSun.com" />
+<meta property="og:image" content="http://example.com/image.jpg" />
+<meta property="article:published_time" content="2017-04-20T14:02:00-07:00" />
+<meta property="article:author" content="https://example.com/staff/jane-doe/" />
+<meta property="article:tag" content="Stadium" />
+<meta name="author" content="Jane Doe">
+<meta name="keywords" content="raiders, stadium">
+<meta name="twitter:card" value="summary">
+<meta name="twitter:site" content="@LasVegasSun" />
+</head>
+<body><p>Hello world.</p></body>
+</html>`
+
+func TestMetadata(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(metadataTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := doc.Metadata
+
+	if exp := "Lease: No rent for Raiders"; m.Title != exp {
+		t.Errorf("expected title '%s' but got '%s'", exp, m.Title)
+	}
+	if exp := "The Raiders would not pay rent."; m.Description != exp {
+		t.Errorf("expected description '%s' but got '%s'", exp, m.Description)
+	}
+	if exp := "LasVegasSun.com"; m.SiteName != exp {
+		t.Errorf("expected site name '%s' but got '%s'", exp, m.SiteName)
+	}
+	if exp := "http://example.com/image.jpg"; m.Image != exp {
+		t.Errorf("expected image '%s' but got '%s'", exp, m.Image)
+	}
+	if exp := "summary"; m.TwitterCard != exp {
+		t.Errorf("expected twitter card '%s' but got '%s'", exp, m.TwitterCard)
+	}
+	if exp := "@LasVegasSun"; m.Get("Twitter:Site") != exp {
+		t.Errorf("expected twitter site '%s' but got '%s'", exp, m.Get("Twitter:Site"))
+	}
+
+	if l := len(m.Authors); l != 2 {
+		t.Fatalf("expected 2 authors but got %d", l)
+	}
+	if l := len(m.Keywords); l != 3 {
+		t.Fatalf("expected 3 keywords but got %d", l)
+	}
+
+	expDate := time.Date(2017, time.April, 20, 21, 2, 0, 0, time.UTC)
+	if !m.DatePublished.Equal(expDate) {
+		t.Errorf("expected date published '%s' but got '%s'", expDate, m.DatePublished)
+	}
+
+	// Metadata is used as a fallback when there is no <title> or JSON-LD
+	if doc.Title != m.Title {
+		t.Errorf("expected document title '%s' but got '%s'", m.Title, doc.Title)
+	}
+	if !doc.Date.Equal(expDate) {
+		t.Errorf("expected document date '%s' but got '%s'", expDate, doc.Date)
+	}
+}
@@ -0,0 +1,492 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// microdataItem is an element with an itemscope attribute and the properties
+// found within it.
+type microdataItem struct {
+	types []string
+	props map[string][]microdataValue
+
+	// depth is the element depth of the itemscope element.
+	depth int
+}
+
+// microdataValue is a property value which is either text, with an optional
+// URL from a link or media element, or a nested item.
+type microdataValue struct {
+	text string
+	url  string
+	item *microdataItem
+}
+
+func (item *microdataItem) add(names []string, v microdataValue) {
+	for _, name := range names {
+		item.props[name] = append(item.props[name], v)
+	}
+}
+
+// microdataCapture is a property whose value is the text content of an
+// element that hasn't ended yet.
+type microdataCapture struct {
+	names []string
+
+	// owner is the item the property belongs to, or nil if the property is
+	// outside of an item or an RDFa property.
+	owner *microdataItem
+
+	// url is the href or src of the element, if any.
+	url string
+
+	depth int
+	buf   bytes.Buffer
+}
+
+// microdata is the state of the microdata and RDFa properties found while
+// parsing a document.
+type microdata struct {
+	items    []*microdataItem
+	scopes   []*microdataItem
+	captures []*microdataCapture
+
+	// props are the properties outside of any item, and RDFa properties.
+	props map[string][]string
+}
+
+func newMicrodata() *microdata {
+	return &microdata{
+		props: make(map[string][]string),
+	}
+}
+
+// microdataAttrs are the microdata and RDFa attributes of an element.
+type microdataAttrs struct {
+	itemscope bool
+	itemtype  string
+	itemprop  string
+	property  string
+
+	// value is the attribute value of the element (e.g. content or
+	// datetime), and hasValue is true if it exists.
+	value    string
+	hasValue bool
+
+	// url is the href or src of a link or media element.
+	url string
+}
+
+func getMicrodataAttrs(tok *html.Token) (attrs microdataAttrs) {
+	valueKey, urlKey := "", ""
+	switch tok.DataAtom {
+	case atom.A, atom.Area, atom.Link:
+		urlKey = "href"
+	case atom.Audio, atom.Embed, atom.Iframe, atom.Img, atom.Source, atom.Track, atom.Video:
+		urlKey = "src"
+	case atom.Object:
+		urlKey = "data"
+	case atom.Time:
+		valueKey = "datetime"
+	case atom.Data, atom.Meter:
+		valueKey = "value"
+	}
+
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "itemscope":
+			attrs.itemscope = true
+		case "itemtype":
+			attrs.itemtype = attr.Val
+		case "itemprop":
+			attrs.itemprop = attr.Val
+		case "property":
+			attrs.property = attr.Val
+		case "content":
+			// The content attribute overrides any other value
+			attrs.value = attr.Val
+			attrs.hasValue = true
+			valueKey = ""
+		case valueKey:
+			attrs.value = attr.Val
+			attrs.hasValue = true
+		case urlKey:
+			attrs.url = strings.TrimSpace(attr.Val)
+		}
+	}
+
+	attrs.value = strings.TrimSpace(attrs.value)
+	return
+}
+
+// MicrodataStartElement collects the microdata and RDFa properties of an
+// element at the given depth. Void elements only contribute properties that
+// have an attribute value.
+func (h *contentHandler) MicrodataStartElement(tok *html.Token, depth int) {
+	md := h.microdata
+	attrs := getMicrodataAttrs(tok)
+
+	var owner *microdataItem
+	if len(md.scopes) > 0 {
+		owner = md.scopes[len(md.scopes)-1]
+	}
+
+	names := strings.Fields(attrs.itemprop)
+
+	if attrs.itemscope {
+		item := &microdataItem{
+			types: trimSchemaTypes(strings.Fields(attrs.itemtype)),
+			props: make(map[string][]microdataValue),
+			depth: depth,
+		}
+		if len(names) > 0 && owner != nil {
+			owner.add(names, microdataValue{item: item})
+		} else {
+			md.items = append(md.items, item)
+		}
+		md.scopes = append(md.scopes, item)
+
+	} else if len(names) > 0 {
+		if attrs.hasValue || (attrs.url != "" && isVoidElement(tok.DataAtom)) {
+			v := microdataValue{text: attrs.value, url: attrs.url}
+			if owner != nil {
+				owner.add(names, v)
+			} else {
+				md.addProps(names, v.String())
+			}
+		} else if !isVoidElement(tok.DataAtom) {
+			md.captures = append(md.captures, &microdataCapture{
+				names: names,
+				owner: owner,
+				url:   attrs.url,
+				depth: depth,
+			})
+		}
+	}
+
+	// RDFa properties in <meta> tags are already collected as metadata
+	if attrs.property != "" && tok.DataAtom != atom.Meta {
+		names := strings.Fields(attrs.property)
+		if attrs.hasValue {
+			md.addProps(names, attrs.value)
+		} else if !isVoidElement(tok.DataAtom) {
+			md.captures = append(md.captures, &microdataCapture{
+				names: names,
+				depth: depth,
+			})
+		}
+	}
+}
+
+// MicrodataText adds text to all of the properties being captured.
+func (h *contentHandler) MicrodataText(text string) {
+	for _, c := range h.microdata.captures {
+		c.buf.WriteString(text)
+	}
+}
+
+// MicrodataEndElement completes the properties and items of an element at the
+// given depth, including any that were left open by descendants.
+func (h *contentHandler) MicrodataEndElement(depth int) {
+	md := h.microdata
+
+	for len(md.captures) > 0 {
+		c := md.captures[len(md.captures)-1]
+		if c.depth < depth {
+			break
+		}
+		md.captures = md.captures[:len(md.captures)-1]
+
+		v := microdataValue{
+			text: strings.Join(strings.Fields(c.buf.String()), " "),
+			url:  c.url,
+		}
+		if c.owner != nil {
+			c.owner.add(c.names, v)
+		} else {
+			md.addProps(c.names, v.String())
+		}
+	}
+
+	for len(md.scopes) > 0 && md.scopes[len(md.scopes)-1].depth >= depth {
+		md.scopes = md.scopes[:len(md.scopes)-1]
+	}
+}
+
+func (md *microdata) addProps(names []string, value string) {
+	for _, name := range names {
+		md.props[name] = append(md.props[name], value)
+	}
+}
+
+// article returns the first schema.org Article item, with any properties it
+// is missing filled in from the properties outside of items, or nil if there
+// are neither.
+func (md *microdata) article() *SchemaArticle {
+	var a *SchemaArticle
+	for _, item := range md.items {
+		if found := findMicrodataArticle(item); found != nil {
+			a = microdataArticle(found)
+			break
+		}
+	}
+
+	if b := propsArticle(md.props); b != nil {
+		if a == nil {
+			a = b
+		} else {
+			mergeSchemaArticle(a, b)
+		}
+	}
+
+	return a
+}
+
+func findMicrodataArticle(item *microdataItem) *microdataItem {
+	if isSchemaArticleType(item.types) {
+		return item
+	}
+	for _, values := range item.props {
+		for _, v := range values {
+			if v.item == nil {
+				continue
+			}
+			if found := findMicrodataArticle(v.item); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+func microdataArticle(item *microdataItem) *SchemaArticle {
+	text := func(name string) string {
+		for _, v := range item.props[name] {
+			if s := v.String(); s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+	texts := func(name string) (texts []string) {
+		for _, v := range item.props[name] {
+			if s := v.String(); s != "" {
+				texts = appendUnique(texts, s)
+			}
+		}
+		return
+	}
+
+	a := &SchemaArticle{
+		Types:       item.types,
+		Headline:    text("headline"),
+		Description: text("description"),
+		Body:        text("articleBody"),
+		Language:    text("inLanguage"),
+		URL:         text("url"),
+		Sections:    texts("articleSection"),
+		Keywords:    splitKeywords(texts("keywords")),
+	}
+
+	if a.Headline == "" {
+		a.Headline = text("name")
+	}
+
+	a.DatePublished = parseSchemaDate(text("datePublished"))
+	a.DateModified = parseSchemaDate(text("dateModified"))
+
+	for _, name := range []string{"author", "creator"} {
+		for _, v := range item.props[name] {
+			if e, ok := v.entity(); ok {
+				a.Authors = append(a.Authors, e)
+			}
+		}
+	}
+
+	for _, v := range item.props["publisher"] {
+		if e, ok := v.entity(); ok {
+			a.Publisher = &e
+			break
+		}
+	}
+
+	for _, v := range item.props["image"] {
+		if u := v.URL(); u != "" {
+			a.Images = appendUnique(a.Images, u)
+		}
+	}
+
+	return a
+}
+
+// String returns the text of the value, or else its URL, or else the name of
+// the item.
+func (v microdataValue) String() string {
+	if v.item != nil {
+		return v.item.first("name")
+	}
+	if v.text != "" {
+		return v.text
+	}
+	return v.url
+}
+
+// URL returns the URL of the value, or else its text, or else the URL of the
+// item.
+func (v microdataValue) URL() string {
+	if v.item != nil {
+		return v.item.first("url", "contentUrl")
+	}
+	if v.url != "" {
+		return v.url
+	}
+	return v.text
+}
+
+// entity returns the value as a Person or Organization.
+func (v microdataValue) entity() (e SchemaEntity, ok bool) {
+	if v.item == nil {
+		e.Name = v.text
+		e.URL = v.url
+		return e, e.Name != "" || e.URL != ""
+	}
+
+	e.Name = v.item.first("name")
+	e.URL = v.item.first("url")
+	if len(v.item.types) > 0 {
+		e.Type = v.item.types[0]
+	}
+	if logos := v.item.props["logo"]; len(logos) > 0 {
+		e.Logo = logos[0].URL()
+	}
+	return e, e.Name != "" || e.URL != ""
+}
+
+// first returns the first non-empty value of the given properties.
+func (item *microdataItem) first(names ...string) string {
+	for _, name := range names {
+		for _, v := range item.props[name] {
+			if v.item != nil {
+				continue
+			}
+			if s := v.String(); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// propsArticleNames maps schema.org Article properties to the equivalent
+// Dublin Core and prefixed schema.org property names.
+var propsArticleNames = map[string][]string{
+	"headline":       {"headline", "schema:headline", "dc:title", "dcterms:title"},
+	"description":    {"description", "schema:description", "dc:description", "dcterms:description", "dcterms:abstract"},
+	"author":         {"author", "schema:author", "creator", "dc:creator", "dcterms:creator"},
+	"datePublished":  {"datePublished", "schema:datePublished", "dc:date", "dcterms:date", "dcterms:created", "dcterms:issued"},
+	"dateModified":   {"dateModified", "schema:dateModified", "dcterms:modified"},
+	"keywords":       {"keywords", "schema:keywords", "dc:subject", "dcterms:subject"},
+	"articleSection": {"articleSection", "schema:articleSection"},
+	"inLanguage":     {"inLanguage", "schema:inLanguage", "dc:language", "dcterms:language"},
+	"publisher":      {"publisher", "schema:publisher", "dc:publisher", "dcterms:publisher"},
+}
+
+// propsArticle returns an article from properties outside of items and RDFa
+// properties, or nil if there are no article properties.
+func propsArticle(props map[string][]string) *SchemaArticle {
+	values := func(name string) (values []string) {
+		for _, key := range propsArticleNames[name] {
+			for _, v := range props[key] {
+				if v != "" {
+					values = appendUnique(values, v)
+				}
+			}
+		}
+		return
+	}
+	text := func(name string) string {
+		if values := values(name); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	a := &SchemaArticle{
+		Headline:    text("headline"),
+		Description: text("description"),
+		Language:    text("inLanguage"),
+		Sections:    values("articleSection"),
+		Keywords:    splitKeywords(values("keywords")),
+	}
+
+	a.DatePublished = parseSchemaDate(text("datePublished"))
+	a.DateModified = parseSchemaDate(text("dateModified"))
+
+	for _, name := range values("author") {
+		a.Authors = append(a.Authors, SchemaEntity{Name: name})
+	}
+	if name := text("publisher"); name != "" {
+		a.Publisher = &SchemaEntity{Name: name}
+	}
+
+	if a.Headline == "" && a.Description == "" && len(a.Authors) == 0 &&
+		a.DatePublished.IsZero() && a.DateModified.IsZero() {
+		return nil
+	}
+	return a
+}
+
+// mergeSchemaArticle fills in the empty fields of a from b.
+func mergeSchemaArticle(a, b *SchemaArticle) {
+	if a.Headline == "" {
+		a.Headline = b.Headline
+	}
+	if a.Description == "" {
+		a.Description = b.Description
+	}
+	if a.Body == "" {
+		a.Body = b.Body
+	}
+	if a.DatePublished.IsZero() {
+		a.DatePublished = b.DatePublished
+	}
+	if a.DateModified.IsZero() {
+		a.DateModified = b.DateModified
+	}
+	if len(a.Authors) == 0 {
+		a.Authors = b.Authors
+	}
+	if a.Publisher == nil {
+		a.Publisher = b.Publisher
+	}
+	if len(a.Images) == 0 {
+		a.Images = b.Images
+	}
+	if len(a.Sections) == 0 {
+		a.Sections = b.Sections
+	}
+	if len(a.Keywords) == 0 {
+		a.Keywords = b.Keywords
+	}
+	if a.Language == "" {
+		a.Language = b.Language
+	}
+	if a.URL == "" {
+		a.URL = b.URL
+	}
+}
+
+func splitKeywords(values []string) (keywords []string) {
+	for _, v := range values {
+		for _, keyword := range strings.Split(v, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = appendUnique(keywords, keyword)
+			}
+		}
+	}
+	return
+}
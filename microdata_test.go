@@ -0,0 +1,106 @@
+package boilerpipe
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const microdataTestHTML = `<html>
+<body>
+<article itemscope itemtype="http://schema.org/NewsArticle">
+  <h1 itemprop="headline">Microdata <em>Headline</em></h1>
+  <a rel="author" href="/staff/jane-doe/" itemprop="author">Jane Doe</a>
+  <span itemprop="author" itemscope itemtype="http://schema.org/Person"><span itemprop="name">John Doe</span></span>
+  <time itemprop="datePublished" datetime="2017-05-22T02:00:00Z">May 22, 2017</time>
+  <meta itemprop="dateModified" content="2017-05-23T02:00:00Z">
+  <img itemprop="image" src="http://example.com/image.jpg">
+  <div itemprop="publisher" itemscope itemtype="http://schema.org/Organization">
+    <meta itemprop="name" content="Example News">
+  </div>
+  <p itemprop="articleBody">Hello world.</p>
+</article>
+</body>
+</html>`
+
+func TestMicrodata(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(microdataTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := doc.Microdata
+	if a == nil {
+		t.Fatal("expected a microdata article")
+	}
+
+	if exp := "Microdata Headline"; a.Headline != exp {
+		t.Errorf("expected headline '%s' but got '%s'", exp, a.Headline)
+	}
+
+	if l := len(a.Authors); l != 2 {
+		t.Fatalf("expected 2 authors but got %d", l)
+	}
+	if a.Authors[0].Name != "Jane Doe" || a.Authors[0].URL != "/staff/jane-doe/" {
+		t.Errorf("unexpected author %+v", a.Authors[0])
+	}
+	if a.Authors[1].Name != "John Doe" || a.Authors[1].Type != "Person" {
+		t.Errorf("unexpected author %+v", a.Authors[1])
+	}
+
+	expDate := time.Date(2017, time.May, 22, 2, 0, 0, 0, time.UTC)
+	if !a.DatePublished.Equal(expDate) {
+		t.Errorf("expected date published '%s' but got '%s'", expDate, a.DatePublished)
+	}
+	if a.DateModified.IsZero() {
+		t.Error("expected date modified")
+	}
+
+	if l := len(a.Images); l != 1 {
+		t.Errorf("expected 1 image but got %d", l)
+	}
+	if a.Publisher == nil || a.Publisher.Name != "Example News" {
+		t.Errorf("unexpected publisher %v", a.Publisher)
+	}
+
+	// Microdata is used in the document metadata resolution
+	if doc.Title != a.Headline {
+		t.Errorf("expected document title '%s' but got '%s'", a.Headline, doc.Title)
+	}
+	if doc.Author != "Jane Doe" {
+		t.Errorf("expected document author 'Jane Doe' but got '%s'", doc.Author)
+	}
+	if !doc.Date.Equal(expDate) {
+		t.Errorf("expected document date '%s' but got '%s'", expDate, doc.Date)
+	}
+}
+
+const rdfaTestHTML = `<html>
+<body>
+<h1 property="dc:title">RDFa Headline</h1>
+<p>By <span property="dc:creator">Jane Doe</span></p>
+<span property="dcterms:created" content="2017-05-22T02:00:00Z">May 22, 2017</span>
+</body>
+</html>`
+
+func TestRDFa(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(rdfaTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := doc.Microdata
+	if a == nil {
+		t.Fatal("expected an RDFa article")
+	}
+
+	if exp := "RDFa Headline"; a.Headline != exp {
+		t.Errorf("expected headline '%s' but got '%s'", exp, a.Headline)
+	}
+	if names := a.AuthorNames(); len(names) != 1 || names[0] != "Jane Doe" {
+		t.Errorf("expected author 'Jane Doe' but got %v", names)
+	}
+	if a.DatePublished.IsZero() {
+		t.Error("expected date published")
+	}
+}
@@ -0,0 +1,74 @@
+package boilerpipe
+
+import (
+	"errors"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ParseNode returns a Document for further processing through filters from
+// an HTML document which has already been parsed, such as by html.Parse.
+//
+// Since the tree has been built by the HTML parsing algorithm, misnested and
+// unclosed elements are handled like a browser would rather than by the
+// tokenizer of ParseDocument. The node is usually the document node, but any
+// other element is parsed as the body of a document, which allows extracting
+// from part of a page.
+func ParseNode(n *html.Node) (*Document, error) {
+	if n == nil {
+		return nil, errors.New("boilerpipe: nil node")
+	}
+
+	h := newContentHandler()
+	if n.Type == html.ElementNode && n.DataAtom != atom.Html && n.DataAtom != atom.Body {
+		h.depthBody++
+	}
+	h.walkNode(n, func(tok *html.Token, h *contentHandler) {
+		h.TextToken(tok)
+	})
+
+	return newDocument(h), nil
+}
+
+// walkNode passes the tokens of a node and its descendants to the content
+// handler, in the order they would be read by the tokenizer.
+func (h *contentHandler) walkNode(n *html.Node, fn func(tok *html.Token, h *contentHandler)) {
+	switch n.Type {
+	case html.DocumentNode:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			h.walkNode(c, fn)
+		}
+
+	case html.ElementNode:
+		tok := html.Token{
+			Type:     html.StartTagToken,
+			DataAtom: n.DataAtom,
+			Data:     n.Data,
+			Attr:     n.Attr,
+		}
+
+		// Void elements have no end tag
+		if isVoidElement(n.DataAtom) {
+			tok.Type = html.SelfClosingTagToken
+			h.handleSelfClosingTag(&tok)
+			return
+		}
+
+		h.handleStartTag(&tok)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			h.walkNode(c, fn)
+		}
+		h.handleEndTag(&html.Token{
+			Type:     html.EndTagToken,
+			DataAtom: n.DataAtom,
+			Data:     n.Data,
+		})
+
+	case html.TextNode:
+		h.handleText(&html.Token{
+			Type: html.TextToken,
+			Data: n.Data,
+		}, fn)
+	}
+}
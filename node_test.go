@@ -0,0 +1,126 @@
+package boilerpipe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestParseNode(t *testing.T) {
+	for i := 0; i <= 6; i++ {
+		path := filepath.Join("testdata", fmt.Sprintf("%d.html", i))
+
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := html.Parse(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err = os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp, err := ParseDocument(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		act, err := ParseNode(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ArticlePipeline.Process(exp)
+		ArticlePipeline.Process(act)
+
+		if act.Title != exp.Title {
+			t.Errorf("%s: expected title '%s' but got '%s'", path, exp.Title, act.Title)
+		}
+		if act.Content() != exp.Content() {
+			t.Errorf("%s: expected content '%s' but got '%s'", path, exp.Content(), act.Content())
+		}
+	}
+}
+
+func TestParseNodeMalformed(t *testing.T) {
+	const s = `<html><body><div><p>The first paragraph</span><p>The second paragraph</div><h2>A heading</h2><p>The third paragraph</body></html>`
+
+	n, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ParseNode(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text     string
+		tagLevel int
+		heading  bool
+	}{
+		{"The first paragraph", 4, false},
+		{"The second paragraph", 4, false},
+		{"A heading", 3, true},
+		{"The third paragraph", 3, false},
+	}
+
+	if len(doc.TextBlocks) != len(tests) {
+		t.Fatalf("expected %d text blocks but got %d", len(tests), len(doc.TextBlocks))
+	}
+	for i, test := range tests {
+		tb := doc.TextBlocks[i]
+		if tb.Text != test.text {
+			t.Errorf("expected text '%s' but got '%s'", test.text, tb.Text)
+		}
+		if tb.TagLevel != test.tagLevel {
+			t.Errorf("%s: expected tag level %d but got %d", test.text, test.tagLevel, tb.TagLevel)
+		}
+		if tb.HasLabel(LabelHeading) != test.heading {
+			t.Errorf("%s: expected heading %t", test.text, test.heading)
+		}
+	}
+}
+
+func TestParseNodeSubtree(t *testing.T) {
+	const s = `<html><head><title>The title</title></head><body><nav><a href="/">Home</a></nav><article><p>The text of the article.</p></article></body></html>`
+
+	n, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var article *html.Node
+	var find func(n *html.Node)
+	find = func(n *html.Node) {
+		if n.DataAtom == atom.Article {
+			article = n
+		}
+		for c := n.FirstChild; c != nil && article == nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+	find(n)
+
+	doc, err := ParseNode(article)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "The text of the article."; doc.Text(true, true) != exp {
+		t.Errorf("expected text '%s' but got '%s'", exp, doc.Text(true, true))
+	}
+
+	if _, err := ParseNode(nil); err == nil {
+		t.Error("expected error for nil node")
+	}
+}
@@ -104,7 +104,10 @@ func NewURL(u *url.URL, options *NormalizeOptions) *URL {
 	}
 }
 
-var _ encoding.TextMarshaler = (*URL)(nil)
+var (
+	_ encoding.TextMarshaler   = (*URL)(nil)
+	_ encoding.TextUnmarshaler = (*URL)(nil)
+)
 
 func (u *URL) MarshalText() ([]byte, error) {
 	if u == nil || u.gu == nil {
@@ -113,6 +116,10 @@ func (u *URL) MarshalText() ([]byte, error) {
 	return []byte(u.String()), nil
 }
 
+func (u *URL) UnmarshalText(text []byte) error {
+	return u.UnmarshalBinary(text)
+}
+
 func Parse(rawurl string) (*URL, error) {
 	gu, err := url.Parse(rawurl)
 	if err != nil {
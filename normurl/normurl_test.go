@@ -108,3 +108,19 @@ func TestIsChild(t *testing.T) {
 		}
 	}
 }
+
+func TestUnmarshalText(t *testing.T) {
+	for rawurl, exp := range parseTestData {
+		var u URL
+		if err := u.UnmarshalText([]byte(rawurl)); err != nil {
+			t.Fatal(err)
+		}
+		text, err := u.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(text) != exp {
+			t.Errorf("expected '%s' but got '%s'", exp, text)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package boilerpipe
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ParseOptions are the limits used when parsing a document, which protect
+// against untrusted documents using too much memory or time. A zero limit
+// means there is no limit.
+type ParseOptions struct {
+	// MaxBytes is the maximum number of bytes read from the document.
+	MaxBytes int64
+
+	// MaxTokens is the maximum number of HTML tokens in the document.
+	MaxTokens int
+
+	// MaxDepth is the maximum nesting depth of the document's elements.
+	MaxDepth int
+
+	// MaxTextBlocks is the maximum number of text blocks in the document.
+	MaxTextBlocks int
+}
+
+// The errors of a ParseError whose kind is ErrLimitExceeded, for each of the
+// limits of ParseOptions.
+var (
+	ErrMaxBytes      = errors.New("boilerpipe: document exceeds MaxBytes")
+	ErrMaxTokens     = errors.New("boilerpipe: document exceeds MaxTokens")
+	ErrMaxDepth      = errors.New("boilerpipe: document exceeds MaxDepth")
+	ErrMaxTextBlocks = errors.New("boilerpipe: document exceeds MaxTextBlocks")
+)
+
+var defaultParseOptions = ParseOptions{}
+
+// ParseDocumentOptions is like ParseDocument, but parses the document with
+// the limits of the options. If a limit is exceeded, the Document of the part
+// which was parsed is returned along with a *ParseError whose kind is
+// ErrLimitExceeded.
+func ParseDocumentOptions(r io.Reader, opts *ParseOptions) (*Document, error) {
+	return ParseDocumentContext(context.Background(), r, opts)
+}
+
+// limitReader reads from r until n bytes have been read, then returns
+// ErrMaxBytes if there is more to read.
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (lr *limitReader) Read(p []byte) (n int, err error) {
+	if lr.n <= 0 {
+		// Check for more data before returning an error
+		var b [1]byte
+		if n, err = lr.r.Read(b[:]); n > 0 {
+			return 0, ErrMaxBytes
+		}
+		return 0, err
+	}
+	if int64(len(p)) > lr.n {
+		p = p[:lr.n]
+	}
+	n, err = lr.r.Read(p)
+	lr.n -= int64(n)
+	return
+}
+
+// exceededLimit returns the error of the limit exceeded by the content
+// handler, or nil if none are exceeded. MaxTokens is checked by parse instead,
+// before each token is handled.
+func (opts *ParseOptions) exceededLimit(h *contentHandler) error {
+	switch {
+	case opts.MaxDepth > 0 && len(h.atomStack.s) > opts.MaxDepth:
+		return ErrMaxDepth
+	case opts.MaxTextBlocks > 0 && len(h.textBlocks) > opts.MaxTextBlocks:
+		return ErrMaxTextBlocks
+	}
+	return nil
+}
+
+// truncate removes the text blocks exceeding the limit once parsing stops.
+func (opts *ParseOptions) truncate(h *contentHandler) {
+	h.FlushBlock()
+	if opts.MaxTextBlocks > 0 && len(h.textBlocks) > opts.MaxTextBlocks {
+		h.textBlocks = h.textBlocks[:opts.MaxTextBlocks]
+	}
+}
@@ -0,0 +1,73 @@
+package boilerpipe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	const s = `<html><head><title>The title</title></head><body>
+<div><div><div><p>The first paragraph.</p></div></div></div>
+<p>The second paragraph.</p>
+<p>The third paragraph.</p>
+</body></html>`
+
+	tests := []struct {
+		opts          ParseOptions
+		exp           error
+		numTextBlocks int
+	}{
+		{ParseOptions{}, nil, 3},
+		{ParseOptions{MaxBytes: int64(len(s))}, nil, 3},
+		{ParseOptions{MaxBytes: int64(strings.Index(s, "<p>The second"))}, ErrMaxBytes, 1},
+		{ParseOptions{MaxTokens: 1000}, nil, 3},
+		{ParseOptions{MaxTokens: 10}, ErrMaxTokens, 0},
+		{ParseOptions{MaxDepth: 6}, nil, 3},
+		{ParseOptions{MaxDepth: 4}, ErrMaxDepth, 0},
+		{ParseOptions{MaxTextBlocks: 3}, nil, 3},
+		{ParseOptions{MaxTextBlocks: 2}, ErrMaxTextBlocks, 2},
+	}
+
+	for _, test := range tests {
+		doc, err := ParseDocumentOptions(strings.NewReader(s), &test.opts)
+		if doc == nil {
+			t.Fatalf("%+v: expected document", test.opts)
+		}
+		if test.exp == nil {
+			if err != nil {
+				t.Errorf("%+v: expected no error but got %v", test.opts, err)
+			}
+		} else if !errors.Is(err, test.exp) || !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%+v: expected error '%v' but got %v", test.opts, test.exp, err)
+		}
+		if l := len(doc.TextBlocks); l != test.numTextBlocks {
+			t.Errorf("%+v: expected %d text blocks but got %d", test.opts, test.numTextBlocks, l)
+		}
+		if exp := "The title"; doc.Title != exp {
+			t.Errorf("%+v: expected title '%s' but got '%s'", test.opts, exp, doc.Title)
+		}
+	}
+}
+
+func TestParseOptionsMaxTokens(t *testing.T) {
+	// Each paragraph is 3 tokens: its start tag, its text and its end tag
+	const s = `<p>1</p><p>2</p><p>3</p><p>4</p>`
+	const numTokens = 12
+
+	for maxTokens := 1; maxTokens <= numTokens; maxTokens++ {
+		doc, err := ParseDocumentOptions(strings.NewReader(s), &ParseOptions{MaxTokens: maxTokens})
+		if maxTokens < numTokens && !errors.Is(err, ErrMaxTokens) {
+			t.Errorf("%d: expected error '%v' but got %v", maxTokens, ErrMaxTokens, err)
+		} else if maxTokens == numTokens && err != nil {
+			t.Errorf("%d: expected no error but got %v", maxTokens, err)
+		}
+
+		// Exactly maxTokens tokens are handled, so the text of every
+		// paragraph whose text token is among them is parsed
+		exp := (maxTokens + 1) / 3
+		if l := len(doc.TextBlocks); l != exp {
+			t.Errorf("%d: expected %d text blocks but got %d", maxTokens, exp, l)
+		}
+	}
+}
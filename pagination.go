@@ -0,0 +1,363 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// reNextPageText matches the text of links to the next page.
+var reNextPageText = regexp.MustCompile(`(?i)^(?:next(?:\s+page)?|nächste(?:\s+seite)?|weiter|suivant(?:e)?|page\s+suivante|siguiente|successiva|volgende|下一页|下页|次へ|次のページ|다음)\s*[›»→>]*$`)
+
+// reNextPageArrow matches the text of links which are only an arrow, which
+// link to the next page unless they're the last link of the pagination
+// container, where they usually link to the last page.
+var reNextPageArrow = regexp.MustCompile(`^[›»→>]$`)
+
+// rePaginationClass matches the class names and ids of pagination
+// containers, such as "pagination", "pager" or "page-numbers".
+var rePaginationClass = regexp.MustCompile(`(?i)(?:^|[-_])(?:pagination|paginator|pager|paging|pages|page[-_]?(?:numbers|nav|links|list))(?:$|[-_])`)
+
+// pageURLOptions are the options page URLs are normalized with, keeping the
+// query since it often contains the page number.
+var pageURLOptions = normurl.NormalizeOptions{KeepQuery: true}
+
+// rePageNumberSuffix matches the page number at the end of a path, such as
+// "/2", "/page/2", "-2" or "_p2", and the file extension.
+var rePageNumberSuffix = regexp.MustCompile(`(?i)(?:[/_-](?:page|seite|pagina|pg|p)?[/_-]?\d{1,3})?(?:\.[a-z]+)?/?$`)
+
+// isSamePageSequence returns true if the URL may be another page of the
+// document at the current URL, since their paths are the same except for a
+// page number. This excludes rel="next" links to the next article of a blog.
+func isSamePageSequence(current, u *url.URL) bool {
+	if current == nil {
+		return true
+	}
+	if !strings.EqualFold(current.Host, u.Host) {
+		return false
+	}
+	a := rePageNumberSuffix.ReplaceAllString(current.Path, "")
+	b := rePageNumberSuffix.ReplaceAllString(u.Path, "")
+	return a == b || strings.HasPrefix(b, a+"/")
+}
+
+// pageLink is a link which may be to another page of the document.
+type pageLink struct {
+	href string
+	rel  bool
+	text string
+
+	// container is the number of the pagination container of the link, or
+	// 0 if it isn't in one.
+	container int
+}
+
+// pagination collects the pagination links of a document while it's parsed.
+type pagination struct {
+	depth      int
+	containers int
+
+	linkDepth int
+	linkHref  string
+	linkRel   bool
+	linkText  bytes.Buffer
+
+	links []pageLink
+}
+
+// PaginationStartElement finds pagination containers and starts collecting
+// the text of links which are in a pagination container or have
+// rel="next".
+func (h *contentHandler) PaginationStartElement(tok *html.Token, depth int) {
+	p := &h.pagination
+
+	if tok.DataAtom != atom.A {
+		if p.depth != 0 {
+			return
+		}
+		for _, attr := range tok.Attr {
+			if (attr.Key == "class" || attr.Key == "id") && rePaginationClass.MatchString(attr.Val) {
+				p.depth = depth
+				p.containers++
+				return
+			}
+		}
+		return
+	}
+
+	if p.linkDepth != 0 {
+		return
+	}
+
+	var href string
+	var rel bool
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "href":
+			href = strings.TrimSpace(attr.Val)
+		case "rel":
+			for _, r := range strings.Fields(strings.ToLower(attr.Val)) {
+				rel = rel || r == "next"
+			}
+		}
+	}
+
+	if href == "" || strings.HasPrefix(href, "#") || !rel && p.depth == 0 {
+		return
+	}
+
+	p.linkDepth = depth
+	p.linkHref = href
+	p.linkRel = rel
+	p.linkText.Reset()
+}
+
+// PaginationText adds text to the pagination link being collected.
+func (h *contentHandler) PaginationText(text string) {
+	if h.pagination.linkDepth != 0 {
+		h.pagination.linkText.WriteString(text)
+	}
+}
+
+// PaginationEndElement completes the pagination link or container at the
+// given depth.
+func (h *contentHandler) PaginationEndElement(depth int) {
+	p := &h.pagination
+
+	if p.linkDepth != 0 && depth <= p.linkDepth {
+		l := pageLink{
+			href: p.linkHref,
+			rel:  p.linkRel,
+			text: strings.Join(strings.Fields(p.linkText.String()), " "),
+		}
+		if p.depth != 0 {
+			l.container = p.containers
+		}
+		p.links = append(p.links, l)
+		p.linkDepth = 0
+	}
+
+	if p.depth != 0 && depth <= p.depth {
+		p.depth = 0
+	}
+}
+
+// nextPageRefs returns the references to the next page, starting with the
+// <link rel="next"> and <a rel="next"> links.
+func (p *pagination) nextPageRefs(linkRefs []string) (refs []string) {
+	refs = append(refs, linkRefs...)
+	for _, l := range p.links {
+		if l.rel {
+			refs = append(refs, l.href)
+		}
+	}
+	for i, l := range p.links {
+		if l.rel {
+			continue
+		}
+		if reNextPageText.MatchString(l.text) || reNextPageArrow.MatchString(l.text) && !p.isLastInContainer(i) {
+			refs = append(refs, l.href)
+		}
+	}
+	return
+}
+
+// isLastInContainer returns true if the link is the last link of its
+// pagination container.
+func (p *pagination) isLastInContainer(i int) bool {
+	l := p.links[i]
+	if l.container == 0 {
+		return false
+	}
+	for _, next := range p.links[i+1:] {
+		if next.container == l.container {
+			return false
+		}
+	}
+	return true
+}
+
+// numberedPageRefs returns the references of the numbered page links, by
+// page number.
+func (p *pagination) numberedPageRefs() map[int]string {
+	refs := make(map[int]string)
+	for _, l := range p.links {
+		n, err := strconv.Atoi(l.text)
+		if err != nil || n < 1 {
+			continue
+		}
+		if _, exists := refs[n]; !exists {
+			refs[n] = l.href
+		}
+	}
+	return refs
+}
+
+// resolvePageURLs resolves the next and numbered page URLs of the document
+// against its base URL.
+func (doc *Document) resolvePageURLs(base *url.URL) {
+	var currentURL *url.URL
+	current := make(map[string]bool)
+	for _, u := range []*normurl.URL{doc.URL, doc.CanonicalURL} {
+		if u != nil {
+			current[u.String()] = true
+			currentURL, _ = url.Parse(u.String())
+		}
+	}
+
+	doc.NextPageURL = nil
+	for _, ref := range doc.nextPageRefs {
+		u := resolveURL(base, ref)
+		if u == nil || !isSamePageSequence(currentURL, u) {
+			continue
+		}
+		if nu := normurl.NewURL(u, &pageURLOptions); !current[nu.String()] {
+			doc.NextPageURL = nu
+			break
+		}
+	}
+
+	numbers := make([]int, 0, len(doc.pageRefs))
+	for n := range doc.pageRefs {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	doc.PageURLs = nil
+	seen := make(map[string]bool)
+	for _, n := range numbers {
+		u := resolveURL(base, doc.pageRefs[n])
+		if u == nil {
+			continue
+		}
+		nu := normurl.NewURL(u, &pageURLOptions)
+		if !seen[nu.String()] {
+			seen[nu.String()] = true
+			doc.PageURLs = append(doc.PageURLs, nu)
+		}
+	}
+}
+
+// A Fetcher fetches the HTML document at a URL.
+type Fetcher interface {
+	Fetch(u *normurl.URL) (io.ReadCloser, error)
+}
+
+// The FetcherFunc type is an adapter to allow the use of ordinary functions
+// as fetchers.
+type FetcherFunc func(u *normurl.URL) (io.ReadCloser, error)
+
+// Fetch calls f(u).
+func (f FetcherFunc) Fetch(u *normurl.URL) (io.ReadCloser, error) {
+	return f(u)
+}
+
+// MergePage appends the content blocks, tables and comments of the next page of the
+// document. Both documents should have been processed by a pipeline first.
+func (doc *Document) MergePage(page *Document) {
+	offset := 0
+	if n := len(doc.TextBlocks); n > 0 {
+		offset = doc.TextBlocks[n-1].OffsetBlocksEnd + 1
+	}
+
+	for _, tb := range page.TextBlocks {
+		if !tb.IsContent {
+			continue
+		}
+		tb.OffsetBlocksStart += offset
+		tb.OffsetBlocksEnd += offset
+		doc.TextBlocks = append(doc.TextBlocks, tb)
+	}
+
+	for _, t := range page.ContentTables() {
+		cp := *t
+		cp.offsetStart += offset
+		cp.offsetEnd += offset
+		doc.Tables = append(doc.Tables, &cp)
+	}
+
+	numComments := len(doc.Comments)
+	for _, c := range page.Comments {
+		if c.Parent != -1 {
+			c.Parent += numComments
+		}
+		doc.Comments = append(doc.Comments, c)
+	}
+
+	if doc.LinkedData != nil && doc.LinkedData.Body != "" {
+		doc.LinkedData.Body += "\n" + page.Content()
+	}
+
+	doc.NextPageURL = page.NextPageURL
+}
+
+// FetchPages follows the next page links of a processed document, fetching
+// up to maxPages additional pages, processing each with the filter and
+// merging its content into the document. It returns the number of pages that
+// were merged. Pages which were already merged are not fetched again.
+func FetchPages(doc *Document, fetcher Fetcher, filter Filter, maxPages int) (n int, err error) {
+	return FetchPagesContext(context.Background(), doc, fetcher, filter, maxPages)
+}
+
+// FetchPagesContext is like FetchPages, but stops fetching pages when the
+// context is done. The pages merged until then are kept and the context's
+// error is returned.
+func FetchPagesContext(ctx context.Context, doc *Document, fetcher Fetcher, filter Filter, maxPages int) (n int, err error) {
+	visited := make(map[string]bool)
+	for _, u := range []*normurl.URL{doc.URL, doc.CanonicalURL} {
+		if u != nil {
+			visited[u.String()] = true
+		}
+	}
+
+	for n < maxPages && doc.NextPageURL != nil && !visited[doc.NextPageURL.String()] {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		u := doc.NextPageURL
+		visited[u.String()] = true
+
+		page, err := fetchPage(ctx, fetcher, u)
+		if err != nil {
+			return n, fmt.Errorf("boilerpipe: error fetching page %s: %w", u, err)
+		}
+		if cf, ok := filter.(ContextFilter); ok {
+			if _, err := cf.ProcessContext(ctx, page); err != nil {
+				return n, err
+			}
+		} else {
+			filter.Process(page)
+		}
+
+		doc.MergePage(page)
+		n++
+	}
+
+	return n, nil
+}
+
+func fetchPage(ctx context.Context, fetcher Fetcher, u *normurl.URL) (*Document, error) {
+	rc, err := fetcher.Fetch(u)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	page, err := ParseDocumentContext(ctx, rc, nil)
+	if err != nil {
+		return nil, err
+	}
+	page.SetURL(u)
+	return page, nil
+}
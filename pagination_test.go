@@ -0,0 +1,191 @@
+package boilerpipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jlubawy/go-boilerpipe/normurl"
+)
+
+const paginationTestHTML = `<html>
+<head>
+<title>A long story</title>
+<link rel="canonical" href="https://example.com/news/long-story">
+%s
+</head>
+<body>
+<p>%s</p>
+<div class="pagination">
+<a href="/news/long-story">1</a>
+<a href="/news/long-story/2">2</a>
+<a href="/news/long-story/3">3</a>
+<a href="%s">Next »</a>
+</div>
+</body>
+</html>`
+
+func paginationPage(n int) string {
+	head, next := "", ""
+	if n < 3 {
+		head = fmt.Sprintf(`<link rel="next" href="/news/long-story/%d">`, n+1)
+		next = fmt.Sprintf("/news/long-story/%d", n+1)
+	}
+	text := fmt.Sprintf("This is the text of page %d of the story, which is long enough to be the content of the page.", n)
+	return fmt.Sprintf(paginationTestHTML, head, text, next)
+}
+
+func TestPagination(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(paginationPage(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := "https://example.com/news/long-story/2"; doc.NextPageURL == nil || doc.NextPageURL.String() != exp {
+		t.Errorf("expected next page '%s' but got %v", exp, doc.NextPageURL)
+	}
+	if l := len(doc.PageURLs); l != 3 {
+		t.Fatalf("expected 3 page URLs but got %v", doc.PageURLs)
+	}
+	if exp := "https://example.com/news/long-story/3"; doc.PageURLs[2].String() != exp {
+		t.Errorf("expected page 3 '%s' but got %v", exp, doc.PageURLs[2])
+	}
+
+	// The next page link is used without a <link rel="next">
+	doc2, err := ParseDocument(strings.NewReader(strings.Replace(paginationPage(1), `<link rel="next"`, `<link rel="prev"`, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc2.NextPageURL == nil || !doc2.NextPageURL.Equal(doc.NextPageURL) {
+		t.Errorf("expected next page %v but got %v", doc.NextPageURL, doc2.NextPageURL)
+	}
+
+	ArticlePipeline.Process(doc)
+
+	var fetched []string
+	fetcher := FetcherFunc(func(u *normurl.URL) (io.ReadCloser, error) {
+		fetched = append(fetched, u.String())
+		n := len(fetched) + 1
+		return io.NopCloser(strings.NewReader(paginationPage(n))), nil
+	})
+
+	n, err := FetchPages(doc, fetcher, ArticlePipeline, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(fetched) != 2 {
+		t.Errorf("expected 2 pages but got %d: %v", n, fetched)
+	}
+	if doc.NextPageURL != nil {
+		t.Errorf("expected no next page but got %v", doc.NextPageURL)
+	}
+
+	content := doc.Content()
+	for i := 1; i <= 3; i++ {
+		if !strings.Contains(content, fmt.Sprintf("page %d of the story", i)) {
+			t.Errorf("expected content of page %d but got '%s'", i, content)
+		}
+	}
+}
+
+func TestNextPageArrow(t *testing.T) {
+	tests := []struct {
+		pager string
+		exp   string
+	}{
+		// The arrow at the end of the pager links to the last page
+		{`<a href="/news/long-story">1</a> <a href="/news/long-story/2">2</a> <a href="/news/long-story/3">3</a> … <a href="/news/long-story/9">»</a>`, ""},
+		{`<a href="/news/long-story/2">›</a> <a href="/news/long-story/9">»</a>`, "https://example.com/news/long-story/2"},
+		{`<a href="/news/long-story/2">»»</a> <a href="/news/long-story/9">9</a>`, ""},
+		{`<a href="/news/long-story/9">9</a> <a href="/news/long-story/2">Next page »</a>`, "https://example.com/news/long-story/2"},
+	}
+	for _, test := range tests {
+		doc, err := ParseDocument(strings.NewReader(`<html><head><link rel="canonical" href="https://example.com/news/long-story"></head><body><p>The text of the story.</p><div class="pager">` + test.pager + `</div></body></html>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var act string
+		if doc.NextPageURL != nil {
+			act = doc.NextPageURL.String()
+		}
+		if act != test.exp {
+			t.Errorf("%s: expected next page '%s' but got '%s'", test.pager, test.exp, act)
+		}
+	}
+}
+
+func TestFetchPagesError(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(paginationPage(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errFetch := errors.New("fetch error")
+	n, err := FetchPages(doc, FetcherFunc(func(u *normurl.URL) (io.ReadCloser, error) {
+		return nil, errFetch
+	}), ArticlePipeline, 1)
+	if n != 0 || !errors.Is(err, errFetch) {
+		t.Errorf("expected fetch error but got %d, %v", n, err)
+	}
+}
+
+func TestFetchPagesContext(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(paginationPage(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ArticlePipeline.Process(doc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var fetched []string
+	fetcher := FetcherFunc(func(u *normurl.URL) (io.ReadCloser, error) {
+		fetched = append(fetched, u.String())
+		n := len(fetched) + 1
+		return io.NopCloser(strings.NewReader(paginationPage(n))), nil
+	})
+
+	// The context is canceled once the second page has been processed
+	processed := 0
+	pipeline := &Pipeline{
+		PipelineName: "Test",
+		Filters:      []Filter{ArticlePipeline, cancelFilter{cancel, &processed}},
+	}
+
+	n, err := FetchPagesContext(ctx, doc, fetcher, pipeline, 10)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled error but got %v", err)
+	}
+	if n != 1 || len(fetched) != 1 {
+		t.Errorf("expected 1 page but got %d: %v", n, fetched)
+	}
+	if content := doc.Content(); !strings.Contains(content, "page 2 of the story") {
+		t.Errorf("expected content of page 2 but got '%s'", content)
+	}
+}
+
+func TestIsSamePageSequence(t *testing.T) {
+	tests := []struct {
+		current, next string
+		exp           bool
+	}{
+		{"https://example.com/story", "https://example.com/story/2", true},
+		{"https://example.com/story.html", "https://example.com/story_2.html", true},
+		{"https://example.com/story/page/2", "https://example.com/story/page/3", true},
+		{"https://example.com/story", "https://example.com/story?page=2", true},
+		{"https://example.com/2006/09/12/story", "https://example.com/2006/09/12/other-story", false},
+		{"https://example.com/story", "https://other.com/story/2", false},
+	}
+	for _, test := range tests {
+		current, _ := url.Parse(test.current)
+		next, _ := url.Parse(test.next)
+		if act := isSamePageSequence(current, next); act != test.exp {
+			t.Errorf("%s -> %s: expected %v but got %v", test.current, test.next, test.exp, act)
+		}
+	}
+}
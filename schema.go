@@ -0,0 +1,332 @@
+package boilerpipe
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaArticle is the metadata of a schema.org Article, or one of its
+// subtypes, found in the document.
+type SchemaArticle struct {
+	// Types are the schema.org types of the article (e.g. NewsArticle).
+	Types []string `json:"types"`
+
+	Headline      string         `json:"headline"`
+	Description   string         `json:"description"`
+	Body          string         `json:"body"`
+	DatePublished time.Time      `json:"datePublished"`
+	DateModified  time.Time      `json:"dateModified"`
+	Authors       []SchemaEntity `json:"authors"`
+	Publisher     *SchemaEntity  `json:"publisher"`
+	Images        []string       `json:"images"`
+	Sections      []string       `json:"sections"`
+	Keywords      []string       `json:"keywords"`
+	Language      string         `json:"language"`
+	URL           string         `json:"url"`
+}
+
+// SchemaEntity is a schema.org Person or Organization.
+type SchemaEntity struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Logo string `json:"logo"`
+}
+
+// AuthorNames returns the names of the article authors.
+func (a *SchemaArticle) AuthorNames() (names []string) {
+	for _, author := range a.Authors {
+		if author.Name != "" {
+			names = appendUnique(names, author.Name)
+		}
+	}
+	return
+}
+
+// schemaArticleTypes are the schema.org Article type and its subtypes.
+var schemaArticleTypes = map[string]bool{
+	"Article":                  true,
+	"AdvertiserContentArticle": true,
+	"AnalysisNewsArticle":      true,
+	"APIReference":             true,
+	"AskPublicNewsArticle":     true,
+	"BackgroundNewsArticle":    true,
+	"BlogPosting":              true,
+	"DiscussionForumPosting":   true,
+	"LiveBlogPosting":          true,
+	"MedicalScholarlyArticle":  true,
+	"NewsArticle":              true,
+	"OpinionNewsArticle":       true,
+	"Report":                   true,
+	"ReportageNewsArticle":     true,
+	"ReviewNewsArticle":        true,
+	"SatiricalArticle":         true,
+	"ScholarlyArticle":         true,
+	"SocialMediaPosting":       true,
+	"TechArticle":              true,
+}
+
+// isSchemaArticleType returns true if any of the types is a schema.org
+// Article type.
+func isSchemaArticleType(types []string) bool {
+	for _, t := range types {
+		if schemaArticleTypes[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLinkedData parses JSON-LD scripts and returns the first schema.org
+// Article found, or nil if none. Articles may be at the top-level, inside of
+// arrays, @graph wrappers or mainEntity properties, and node references by
+// @id are resolved across all of the scripts.
+func parseLinkedData(scripts []string) *SchemaArticle {
+	var roots []interface{}
+	for _, s := range scripts {
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			continue // try the next if multiple
+		}
+		roots = append(roots, v)
+	}
+
+	p := &linkedDataParser{
+		nodes:   make(map[string]map[string]interface{}),
+		visited: make(map[string]bool),
+	}
+	for _, v := range roots {
+		p.index(v)
+	}
+	for _, v := range roots {
+		if node := p.findArticle(v); node != nil {
+			return p.article(node)
+		}
+	}
+	return nil
+}
+
+type linkedDataParser struct {
+	// nodes maps @id to the node with that identifier.
+	nodes map[string]map[string]interface{}
+
+	visited map[string]bool
+}
+
+func (p *linkedDataParser) index(v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			p.index(e)
+		}
+	case map[string]interface{}:
+		if id, ok := v["@id"].(string); ok && len(v) > 1 {
+			if _, exists := p.nodes[id]; !exists {
+				p.nodes[id] = v
+			}
+		}
+		for _, e := range v {
+			p.index(e)
+		}
+	}
+}
+
+// resolve returns the node referenced by v if it is an @id reference,
+// otherwise v is returned unchanged.
+func (p *linkedDataParser) resolve(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		if id, ok := m["@id"].(string); ok && len(m) == 1 {
+			if node, exists := p.nodes[id]; exists {
+				return node
+			}
+		}
+	}
+	return v
+}
+
+func (p *linkedDataParser) findArticle(v interface{}) map[string]interface{} {
+	switch v := p.resolve(v).(type) {
+	case []interface{}:
+		for _, e := range v {
+			if node := p.findArticle(e); node != nil {
+				return node
+			}
+		}
+	case map[string]interface{}:
+		if isSchemaArticleType(schemaTypes(v)) {
+			return v
+		}
+		if id, ok := v["@id"].(string); ok {
+			// Guard against cyclic references
+			if p.visited[id] {
+				return nil
+			}
+			p.visited[id] = true
+		}
+		for _, key := range []string{"@graph", "mainEntity"} {
+			if node := p.findArticle(v[key]); node != nil {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+func (p *linkedDataParser) article(node map[string]interface{}) *SchemaArticle {
+	a := &SchemaArticle{
+		Types:       schemaTypes(node),
+		Headline:    p.text(node["headline"]),
+		Description: p.text(node["description"]),
+		Body:        p.text(node["articleBody"]),
+		Language:    p.text(node["inLanguage"]),
+		URL:         p.text(node["url"]),
+		Sections:    p.texts(node["articleSection"]),
+		Keywords:    p.keywords(node["keywords"]),
+	}
+
+	if a.Headline == "" {
+		a.Headline = p.text(node["name"])
+	}
+
+	a.DatePublished = parseSchemaDate(p.text(node["datePublished"]))
+	a.DateModified = parseSchemaDate(p.text(node["dateModified"]))
+
+	a.Authors = append(a.Authors, p.entities(node["author"])...)
+	a.Authors = append(a.Authors, p.entities(node["creator"])...)
+
+	if publishers := p.entities(node["publisher"]); len(publishers) > 0 {
+		a.Publisher = &publishers[0]
+	}
+
+	for _, image := range p.urls(node["image"]) {
+		a.Images = appendUnique(a.Images, image)
+	}
+
+	return a
+}
+
+// text returns the first string value of v.
+func (p *linkedDataParser) text(v interface{}) string {
+	if texts := p.texts(v); len(texts) > 0 {
+		return texts[0]
+	}
+	return ""
+}
+
+// texts returns the string values of v, which can be a string, a number, a
+// @value object or an array of those.
+func (p *linkedDataParser) texts(v interface{}) (texts []string) {
+	switch v := p.resolve(v).(type) {
+	case string:
+		if s := strings.TrimSpace(v); s != "" {
+			texts = append(texts, s)
+		}
+	case float64:
+		texts = append(texts, strconv.FormatFloat(v, 'f', -1, 64))
+	case []interface{}:
+		for _, e := range v {
+			texts = append(texts, p.texts(e)...)
+		}
+	case map[string]interface{}:
+		if value, ok := v["@value"]; ok {
+			texts = append(texts, p.texts(value)...)
+		}
+	}
+	return
+}
+
+// keywords returns the keywords of v, which can be a comma-separated string
+// or an array of strings.
+func (p *linkedDataParser) keywords(v interface{}) []string {
+	return splitKeywords(p.texts(v))
+}
+
+// entities returns the Persons or Organizations of v, which can be a name, an
+// object or an array of those.
+func (p *linkedDataParser) entities(v interface{}) (entities []SchemaEntity) {
+	switch v := p.resolve(v).(type) {
+	case string:
+		if s := strings.TrimSpace(v); s != "" {
+			entities = append(entities, SchemaEntity{Name: s})
+		}
+	case []interface{}:
+		for _, e := range v {
+			entities = append(entities, p.entities(e)...)
+		}
+	case map[string]interface{}:
+		e := SchemaEntity{
+			Name: p.text(v["name"]),
+			URL:  p.text(v["url"]),
+		}
+		if types := schemaTypes(v); len(types) > 0 {
+			e.Type = types[0]
+		}
+		if logos := p.urls(v["logo"]); len(logos) > 0 {
+			e.Logo = logos[0]
+		}
+		if e.Name != "" || e.URL != "" {
+			entities = append(entities, e)
+		}
+	}
+	return
+}
+
+// urls returns the URLs of v, which can be a URL, an ImageObject or an array
+// of those.
+func (p *linkedDataParser) urls(v interface{}) (urls []string) {
+	switch v := p.resolve(v).(type) {
+	case string:
+		if s := strings.TrimSpace(v); s != "" {
+			urls = append(urls, s)
+		}
+	case []interface{}:
+		for _, e := range v {
+			urls = append(urls, p.urls(e)...)
+		}
+	case map[string]interface{}:
+		if u := p.text(v["url"]); u != "" {
+			urls = append(urls, u)
+		} else if u := p.text(v["contentUrl"]); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return
+}
+
+// schemaTypes returns the @type values of a node without any schema.org
+// prefix.
+func schemaTypes(node map[string]interface{}) []string {
+	var types []string
+	switch v := node["@type"].(type) {
+	case string:
+		types = append(types, v)
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	return trimSchemaTypes(types)
+}
+
+// trimSchemaTypes removes any schema.org prefix (e.g. http://schema.org/ or
+// schema:) from the types.
+func trimSchemaTypes(types []string) (trimmed []string) {
+	for _, s := range types {
+		if i := strings.LastIndexAny(s, "/:"); i != -1 {
+			s = s[i+1:]
+		}
+		if s != "" {
+			trimmed = append(trimmed, s)
+		}
+	}
+	return
+}
+
+func parseSchemaDate(s string) time.Time {
+	t, _ := ParseDate(s)
+	return t
+}
@@ -0,0 +1,129 @@
+package boilerpipe
+
+import (
+	"testing"
+	"time"
+)
+
+var linkedDataTestData = []struct {
+	Name       string
+	Scripts    []string
+	ExpTypes   []string
+	ExpTitle   string
+	ExpAuthors []string
+}{
+	{
+		"Article",
+		[]string{`{"@context":"http://schema.org","@type":"Article","headline":"Title","author":{"@type":"Person","name":"Jane Doe"}}`},
+		[]string{"Article"},
+		"Title",
+		[]string{"Jane Doe"},
+	},
+	{
+		"NewsArticle with author string",
+		[]string{`{"@type":"NewsArticle","headline":"Title","author":"Jane Doe"}`},
+		[]string{"NewsArticle"},
+		"Title",
+		[]string{"Jane Doe"},
+	},
+	{
+		"Top-level array with multiple authors",
+		[]string{`[{"@type":"WebSite","name":"Site"},{"@type":"BlogPosting","headline":"Title","author":[{"@type":"Person","name":"Jane Doe"},"John Doe"]}]`},
+		[]string{"BlogPosting"},
+		"Title",
+		[]string{"Jane Doe", "John Doe"},
+	},
+	{
+		"@graph with @id references and @type array",
+		[]string{
+			`{"@type":"WebSite","name":"Site"}`,
+			`{"@context":"https://schema.org","@graph":[{"@type":"WebPage","@id":"#webpage","mainEntity":{"@id":"#article"}},{"@type":["ReportageNewsArticle","http://schema.org/Article"],"@id":"#article","headline":"Title","author":{"@id":"#jane"}},{"@type":"Person","@id":"#jane","name":"Jane Doe"}]}`,
+		},
+		[]string{"ReportageNewsArticle", "Article"},
+		"Title",
+		[]string{"Jane Doe"},
+	},
+	{
+		"Invalid JSON followed by an Article",
+		[]string{`{"@type":`, `{"@type":"Article","headline":"Title"}`},
+		[]string{"Article"},
+		"Title",
+		nil,
+	},
+}
+
+func TestParseLinkedData(t *testing.T) {
+	for _, d := range linkedDataTestData {
+		a := parseLinkedData(d.Scripts)
+		if a == nil {
+			t.Errorf("%s: expected an article", d.Name)
+			continue
+		}
+
+		if len(a.Types) != len(d.ExpTypes) {
+			t.Errorf("%s: expected types %v but got %v", d.Name, d.ExpTypes, a.Types)
+		} else {
+			for i := range a.Types {
+				if a.Types[i] != d.ExpTypes[i] {
+					t.Errorf("%s: expected types %v but got %v", d.Name, d.ExpTypes, a.Types)
+					break
+				}
+			}
+		}
+
+		if a.Headline != d.ExpTitle {
+			t.Errorf("%s: expected headline '%s' but got '%s'", d.Name, d.ExpTitle, a.Headline)
+		}
+
+		names := a.AuthorNames()
+		if len(names) != len(d.ExpAuthors) {
+			t.Errorf("%s: expected authors %v but got %v", d.Name, d.ExpAuthors, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != d.ExpAuthors[i] {
+				t.Errorf("%s: expected authors %v but got %v", d.Name, d.ExpAuthors, names)
+				break
+			}
+		}
+	}
+}
+
+func TestParseLinkedDataFields(t *testing.T) {
+	a := parseLinkedData([]string{`{
+		"@type": "NewsArticle",
+		"headline": "Title",
+		"datePublished": "2019-03-28T08:58:14+08:00",
+		"dateModified": "2019-03-29T10:00:00+08:00",
+		"articleSection": ["World", "Politics"],
+		"keywords": "one, two,three",
+		"image": [{"@type":"ImageObject","url":"http://example.com/a.jpg"},"http://example.com/b.jpg"],
+		"publisher": {"@type":"Organization","name":"Example News","logo":{"@type":"ImageObject","url":"http://example.com/logo.png"}}
+	}`})
+	if a == nil {
+		t.Fatal("expected an article")
+	}
+
+	expDate := time.Date(2019, time.March, 28, 0, 58, 14, 0, time.UTC)
+	if !a.DatePublished.Equal(expDate) {
+		t.Errorf("expected date published '%s' but got '%s'", expDate, a.DatePublished)
+	}
+	if a.DateModified.IsZero() {
+		t.Error("expected date modified")
+	}
+	if l := len(a.Sections); l != 2 {
+		t.Errorf("expected 2 sections but got %d", l)
+	}
+	if l := len(a.Keywords); l != 3 {
+		t.Errorf("expected 3 keywords but got %d", l)
+	}
+	if l := len(a.Images); l != 2 {
+		t.Errorf("expected 2 images but got %d", l)
+	}
+	if a.Publisher == nil {
+		t.Fatal("expected a publisher")
+	}
+	if a.Publisher.Name != "Example News" || a.Publisher.Logo != "http://example.com/logo.png" {
+		t.Errorf("unexpected publisher %+v", *a.Publisher)
+	}
+}
@@ -0,0 +1,162 @@
+package boilerpipe
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A Sentence is a sentence of a text block. Start and End are the character
+// (rune) offsets of the sentence in the text of the block, such that
+// Text == string([]rune(block.Text)[Start:End]).
+type Sentence struct {
+	Text  string
+	Block int
+	Start int
+	End   int
+}
+
+// abbreviations are the lowercase abbreviations, without their final period,
+// which do not end a sentence.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true,
+	"jr": true, "st": true, "mt": true, "rev": true, "gen": true, "gov": true,
+	"sen": true, "rep": true, "sgt": true, "capt": true, "col": true, "lt": true,
+	"vs": true, "etc": true, "inc": true, "ltd": true, "co": true, "corp": true,
+	"no": true, "nos": true, "fig": true, "vol": true, "pp": true, "approx": true,
+	"jan": true, "feb": true, "mar": true, "apr": true, "jun": true, "jul": true,
+	"aug": true, "sep": true, "sept": true, "oct": true, "nov": true, "dec": true,
+	"ala": true, "ariz": true, "calif": true, "colo": true, "conn": true,
+	"fla": true, "ill": true, "mass": true, "mich": true, "minn": true,
+	"nev": true, "okla": true, "ore": true, "pa": true, "tenn": true,
+	"tex": true, "va": true, "wash": true, "wis": true,
+	"bzw": true, "ca": true, "dh": true, "usw": true, "vgl": true, "z": true,
+}
+
+// isSentenceTerminal returns true if the rune ends a sentence when followed
+// by whitespace.
+func isSentenceTerminal(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
+
+// isCJKSentenceTerminal returns true if the rune ends a sentence regardless
+// of the following rune.
+func isCJKSentenceTerminal(r rune) bool {
+	return r == '。' || r == '！' || r == '？'
+}
+
+// isSentenceCloser returns true if the rune may follow the end of a sentence
+// while still being part of it, such as a closing quote or parenthesis.
+func isSentenceCloser(r rune) bool {
+	return strings.ContainsRune(`"')]”’»」』）】`, r)
+}
+
+// SplitSentences splits text into sentences at sentence-ending punctuation
+// and line breaks. A period does not end a sentence after an abbreviation or
+// initial, or when followed by a lowercase letter. Chinese and Japanese full
+// stops always end a sentence. The Block of each sentence is zero.
+func SplitSentences(text string) (sentences []Sentence) {
+	runes := []rune(text)
+
+	start := 0
+	add := func(end int) {
+		// Trim the surrounding whitespace
+		s, e := start, end
+		for s < e && unicode.IsSpace(runes[s]) {
+			s++
+		}
+		for e > s && unicode.IsSpace(runes[e-1]) {
+			e--
+		}
+		if s < e {
+			sentences = append(sentences, Sentence{
+				Text:  string(runes[s:e]),
+				Start: s,
+				End:   e,
+			})
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '\n' {
+			add(i)
+			continue
+		}
+
+		cjk := isCJKSentenceTerminal(r)
+		if !cjk && !isSentenceTerminal(r) {
+			continue
+		}
+
+		// Include repeated terminals and closing quotes in the sentence
+		end := i + 1
+		for end < len(runes) && (isSentenceTerminal(runes[end]) || isCJKSentenceTerminal(runes[end]) || isSentenceCloser(runes[end])) {
+			end++
+		}
+
+		if !cjk {
+			if end < len(runes) && !unicode.IsSpace(runes[end]) {
+				continue
+			}
+			if r == '.' && !isSentenceEndPeriod(runes, start, i, end) {
+				continue
+			}
+		}
+
+		add(end)
+		i = end - 1
+	}
+	add(len(runes))
+
+	return
+}
+
+// isSentenceEndPeriod returns true if the period at index i, within the
+// sentence starting at start and ending at end, ends the sentence.
+func isSentenceEndPeriod(runes []rune, start, i, end int) bool {
+	// The word before the period
+	j := i
+	for j > start && !unicode.IsSpace(runes[j-1]) && !isSentenceCloser(runes[j-1]) && runes[j-1] != '(' {
+		j--
+	}
+	word := string(runes[j:i])
+
+	switch {
+	case word == "":
+	case abbreviations[strings.ToLower(word)]:
+		return false
+	case strings.Contains(word, "."):
+		// Dotted abbreviations such as "U.S." or "e.g."
+		return false
+	case len([]rune(word)) == 1 && unicode.IsUpper([]rune(word)[0]):
+		// Initials such as "J. Smith"
+		return false
+	}
+
+	// The first letter after the period
+	for k := end; k < len(runes); k++ {
+		r := runes[k]
+		if unicode.IsSpace(r) || isSentenceCloser(r) || r == '(' || r == '"' || r == '“' {
+			continue
+		}
+		return !unicode.IsLower(r)
+	}
+	return true
+}
+
+// Sentences returns the sentences of the text blocks, like Text, with the
+// index of their block.
+func (doc *Document) Sentences(includeContent, includeNonContent bool) (sentences []Sentence) {
+	for i, tb := range doc.TextBlocks {
+		if tb.IsContent && !includeContent || !tb.IsContent && !includeNonContent {
+			continue
+		}
+		for _, s := range SplitSentences(tb.Text) {
+			s.Block = i
+			sentences = append(sentences, s)
+		}
+	}
+	return
+}
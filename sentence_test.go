@@ -0,0 +1,62 @@
+package boilerpipe
+
+import (
+	"strings"
+	"testing"
+)
+
+var splitSentencesTestData = map[string][]string{
+	"One sentence. Two sentences! Three?":                       {"One sentence.", "Two sentences!", "Three?"},
+	"He said \"stop.\" Then he left.":                           {"He said \"stop.\"", "Then he left."},
+	"Mr. Smith met Dr. Jones in Los Angeles, Calif. on Friday.": {"Mr. Smith met Dr. Jones in Los Angeles, Calif. on Friday."},
+	"Big Names Pull Cash from V.C. Fund. It was 3.5 percent.":   {"Big Names Pull Cash from V.C. Fund.", "It was 3.5 percent."},
+	"J. R. R. Tolkien wrote it... and then? Nothing.":           {"J. R. R. Tolkien wrote it... and then?", "Nothing."},
+	"First paragraph\nSecond paragraph.":                        {"First paragraph", "Second paragraph."},
+	"今年政府工作报告提出。交通运输部已成立！是吗？":                                   {"今年政府工作报告提出。", "交通运输部已成立！", "是吗？"},
+	"他说：“世界好，中国才能好。”然后离开了。":                                     {"他说：“世界好，中国才能好。”", "然后离开了。"},
+	"No punctuation": {"No punctuation"},
+	"":               nil,
+}
+
+func TestSplitSentences(t *testing.T) {
+	for text, exp := range splitSentencesTestData {
+		act := SplitSentences(text)
+		if len(act) != len(exp) {
+			t.Errorf("%s: expected %q but got %+v", text, exp, act)
+			continue
+		}
+
+		runes := []rune(text)
+		for i, s := range act {
+			if s.Text != exp[i] {
+				t.Errorf("%s: expected sentence '%s' but got '%s'", text, exp[i], s.Text)
+			}
+			if string(runes[s.Start:s.End]) != s.Text {
+				t.Errorf("%s: expected offsets %d:%d to be '%s'", text, s.Start, s.End, s.Text)
+			}
+		}
+	}
+}
+
+func TestDocumentSentences(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(`<html><body><h1>Title</h1><p>First one. Second one.</p><p>第一句。第二句。</p></body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.TextBlocks[0].IsContent = false
+	doc.TextBlocks[1].IsContent = true
+	doc.TextBlocks[2].IsContent = true
+
+	sentences := doc.Sentences(true, false)
+	if len(sentences) != 4 {
+		t.Fatalf("expected 4 sentences but got %+v", sentences)
+	}
+
+	exp := Sentence{Text: "第二句。", Block: 2, Start: 4, End: 8}
+	if sentences[3] != exp {
+		t.Errorf("expected %+v but got %+v", exp, sentences[3])
+	}
+	if sentences[1].Block != 1 || sentences[1].Start != 11 {
+		t.Errorf("unexpected sentence %+v", sentences[1])
+	}
+}
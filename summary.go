@@ -0,0 +1,176 @@
+package boilerpipe
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// minLeadWords is the minimum number of words of the lead paragraph.
+	minLeadWords = 15
+
+	// minLeadRunes is the minimum number of characters of the lead paragraph
+	// when it's written without spaces between words (e.g. Chinese).
+	minLeadRunes = 40
+
+	// minSummaryWords is the minimum number of words of a summary sentence.
+	minSummaryWords = 5
+)
+
+// contentParagraphs returns the paragraphs of the content blocks, which may
+// each contain several paragraphs once they've been fused, excluding the
+// title and bylines.
+func (doc *Document) contentParagraphs() (paragraphs []string) {
+	potentialTitles := make(map[string]bool)
+	for _, pot := range titleCandidates(doc.Title) {
+		potentialTitles[normalizeTitle(pot)] = true
+	}
+
+	for _, tb := range doc.TextBlocks {
+		if !tb.IsContent {
+			continue
+		}
+		for _, p := range strings.Split(tb.Text, "\n") {
+			p = strings.TrimSpace(p)
+			if p == "" || matchesTitle(potentialTitles, p) || len(strings.Fields(p)) <= maxBylineWords && len(bylineAuthors(p)) > 0 {
+				continue
+			}
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return
+}
+
+// isSubstantive returns true if the text is long enough to be a paragraph
+// rather than a heading, caption or dateline.
+func isSubstantive(text string) bool {
+	if isLinkText(text) {
+		return false
+	}
+	if strings.IndexFunc(text, isCJK) != -1 {
+		return utf8.RuneCountInString(text) >= minLeadRunes
+	}
+	return len(strings.Fields(text)) >= minLeadWords
+}
+
+// isLinkText returns true if the text only contains a URL.
+func isLinkText(text string) bool {
+	return strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://")
+}
+
+// Lead returns the lead paragraph of the document, which is the first
+// substantive paragraph of its content, or an empty string if there is none.
+// The document must have been processed by a pipeline first.
+func (doc *Document) Lead() string {
+	for _, p := range doc.contentParagraphs() {
+		if isSubstantive(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// summaryTerms returns the lowercase words of a text, excluding the stop
+// words of the language.
+func summaryTerms(text, lang string) (terms []string) {
+	var stopWords map[string]bool
+	if p := languageProfiles[lang]; p != nil {
+		stopWords = p.stopWords
+	}
+
+	for _, tok := range SplitWords(text, lang) {
+		tok = strings.ToLower(tok)
+		if !stopWords[tok] {
+			terms = append(terms, tok)
+		}
+	}
+	return
+}
+
+// Summary returns up to n sentences of the substantive paragraphs of the
+// document content which best summarize it, in the order they appear, or nil
+// if n is not positive. Sentences are scored by their position, favoring the
+// start of the content and of each paragraph, and by the average frequency of
+// their words in the content. The document must have been processed by a
+// pipeline first.
+func (doc *Document) Summary(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	type sentence struct {
+		text  string
+		index int
+		terms []string
+		score float64
+	}
+
+	var sentences []*sentence
+	freqs := make(map[string]int)
+	firstInParagraph := make(map[int]bool)
+	seen := make(map[string]bool)
+
+	// Headings, captions and datelines are not summarized
+	for _, p := range doc.contentParagraphs() {
+		if !isSubstantive(p) {
+			continue
+		}
+		firstInParagraph[len(sentences)] = true
+		for _, ps := range SplitSentences(p) {
+			s := ps.Text
+			if seen[s] {
+				continue
+			}
+			seen[s] = true
+
+			terms := summaryTerms(s, doc.Language)
+			for _, term := range terms {
+				freqs[term]++
+			}
+			sentences = append(sentences, &sentence{
+				text:  s,
+				index: len(sentences),
+				terms: terms,
+			})
+		}
+	}
+
+	candidates := make([]*sentence, 0, len(sentences))
+	for _, s := range sentences {
+		if len(s.terms) < minSummaryWords {
+			continue
+		}
+
+		var sum float64
+		for _, term := range s.terms {
+			sum += math.Log(1 + float64(freqs[term]))
+		}
+		s.score = sum / float64(len(s.terms))
+
+		// Earlier sentences are more likely to summarize the content
+		s.score *= 1 + 1/math.Sqrt(float64(1+s.index))
+		if firstInParagraph[s.index] {
+			s.score *= 1.2
+		}
+
+		candidates = append(candidates, s)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if n < len(candidates) {
+		candidates = candidates[:n]
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].index < candidates[j].index
+	})
+
+	summary := make([]string, len(candidates))
+	for i, s := range candidates {
+		summary[i] = s.text
+	}
+	return summary
+}
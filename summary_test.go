@@ -0,0 +1,62 @@
+package boilerpipe
+
+import (
+	"strings"
+	"testing"
+)
+
+const summaryTestHTML = `<html>
+<head>
+<title>Council approves new park - Example News</title>
+<meta name="description" content="The standard description.">
+<meta property="og:description" content="The city council approved a new park.">
+</head>
+<body>
+<h1>Council approves new park</h1>
+<p>By Jane Doe</p>
+<p>The city council approved a new park on Tuesday after a long debate. The park will be built on the site of the old rail yard. Construction of the park is expected to start next spring.</p>
+<p>Residents had asked the council for a park for many years. Several residents spoke at the meeting in support of the park. The council voted seven to two in favor.</p>
+<p>The mayor said the new park would cost about two million dollars. Money for the park will come from the city budget and from donations.</p>
+</body>
+</html>`
+
+func TestSummary(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(summaryTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tb := range doc.TextBlocks {
+		tb.IsContent = true
+	}
+
+	if exp := "The city council approved a new park."; doc.Description != exp {
+		t.Errorf("expected description '%s' but got '%s'", exp, doc.Description)
+	}
+
+	if lead := doc.Lead(); !strings.HasPrefix(lead, "The city council approved") {
+		t.Errorf("expected the first paragraph as the lead but got '%s'", lead)
+	}
+
+	summary := doc.Summary(2)
+	if len(summary) != 2 {
+		t.Fatalf("expected 2 sentences but got %d: %v", len(summary), summary)
+	}
+	if exp := "The city council approved a new park on Tuesday after a long debate."; summary[0] != exp {
+		t.Errorf("expected first sentence '%s' but got '%s'", exp, summary[0])
+	}
+	for _, s := range summary {
+		if s == "Council approves new park" || s == "By Jane Doe" {
+			t.Errorf("expected title and byline to be excluded but got '%s'", s)
+		}
+	}
+
+	if l := len(doc.Summary(100)); l != 8 {
+		t.Errorf("expected 8 sentences but got %d", l)
+	}
+
+	for _, n := range []int{0, -1} {
+		if summary := doc.Summary(n); summary != nil {
+			t.Errorf("%d: expected no sentences but got %v", n, summary)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package boilerpipe
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// A Table is a data table found in a document.
+type Table struct {
+	Caption string `json:"caption"`
+
+	// Header are the cells of the header row of the table, or nil if it has
+	// none.
+	Header []string `json:"header"`
+
+	// Rows are the cells of the rows of the table, excluding the header. All
+	// rows have the same number of cells as the header.
+	Rows [][]string `json:"rows"`
+
+	// offsetStart and offsetEnd are the offsets of the text blocks of the
+	// table.
+	offsetStart int
+	offsetEnd   int
+}
+
+// Markdown returns the table as a Markdown table. Tables without a header row
+// are given an empty one since Markdown tables require it.
+func (t *Table) Markdown() string {
+	buf := &bytes.Buffer{}
+
+	writeRow := func(cells []string) {
+		buf.WriteString("|")
+		for _, cell := range cells {
+			cell = strings.ReplaceAll(cell, "|", `\|`)
+			buf.WriteString(" " + cell + " |")
+		}
+		buf.WriteString("\n")
+	}
+
+	header := t.Header
+	if header == nil {
+		header = make([]string, t.numColumns())
+	}
+	writeRow(header)
+
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(sep)
+
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+
+	return buf.String()
+}
+
+// CSV returns the table as comma-separated values, starting with the header
+// row if there is one.
+func (t *Table) CSV() string {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if t.Header != nil {
+		w.Write(t.Header)
+	}
+	w.WriteAll(t.Rows)
+	return buf.String()
+}
+
+func (t *Table) numColumns() int {
+	if t.Header != nil {
+		return len(t.Header)
+	}
+	if len(t.Rows) > 0 {
+		return len(t.Rows[0])
+	}
+	return 0
+}
+
+// ContentTables returns the tables within the content of the document, which
+// are the tables between its first and last content text blocks.
+func (doc *Document) ContentTables() (tables []*Table) {
+	start, end := -1, -1
+	for _, tb := range doc.TextBlocks {
+		if !tb.IsContent {
+			continue
+		}
+		if start == -1 {
+			start = tb.OffsetBlocksStart
+		}
+		end = tb.OffsetBlocksEnd
+	}
+	if start == -1 {
+		return nil
+	}
+
+	for _, t := range doc.Tables {
+		if t.offsetEnd >= start && t.offsetStart <= end {
+			tables = append(tables, t)
+		}
+	}
+	return
+}
+
+const (
+	// maxTableCellWords is the maximum number of words in a cell of a data
+	// table, since layout tables contain whole sections of a page.
+	maxTableCellWords = 80
+
+	// maxTableLinkDensity is the maximum ratio of linked text in a data
+	// table, since navigation is often laid out with tables.
+	maxTableLinkDensity = 0.5
+)
+
+// tableCell is a cell of a table being parsed.
+type tableCell struct {
+	buf     bytes.Buffer
+	header  bool
+	colspan int
+}
+
+// tableRow is a row of a table being parsed.
+type tableRow struct {
+	cells  []string
+	inHead bool
+
+	numCells       int
+	numHeaderCells int
+}
+
+// isHeader returns true if the row is in the <thead> of the table or all of
+// its cells are header cells.
+func (row *tableRow) isHeader() bool {
+	return row.inHead || row.numCells > 0 && row.numHeaderCells == row.numCells
+}
+
+// tableState is a table being parsed.
+type tableState struct {
+	depth       int
+	offsetStart int
+
+	// layout is true if the table is nested, contains a table or has a
+	// presentation role.
+	layout bool
+
+	captionDepth int
+	caption      bytes.Buffer
+
+	headDepth int
+	rowDepth  int
+	row       *tableRow
+	rows      []*tableRow
+
+	cellDepth int
+	cell      tableCell
+
+	linkDepth      int
+	numChars       int
+	numLinkedChars int
+	maxCellWords   int
+}
+
+// tables collects the data tables of a document while it's parsed.
+type tables struct {
+	stack  []*tableState
+	tables []*Table
+}
+
+// TableStartElement starts a table, or a caption, row or cell of the
+// innermost table being parsed.
+func (h *contentHandler) TableStartElement(tok *html.Token, depth int) {
+	ts := &h.tables
+
+	if tok.DataAtom == atom.Table {
+		t := &tableState{
+			depth:       depth,
+			offsetStart: h.offsetBlocks,
+			layout:      len(ts.stack) > 0,
+		}
+		for _, attr := range tok.Attr {
+			if attr.Key == "role" && (attr.Val == "presentation" || attr.Val == "none") {
+				t.layout = true
+			}
+		}
+		for _, outer := range ts.stack {
+			outer.layout = true
+		}
+		ts.stack = append(ts.stack, t)
+		return
+	}
+
+	if len(ts.stack) == 0 {
+		return
+	}
+	t := ts.stack[len(ts.stack)-1]
+
+	switch tok.DataAtom {
+	case atom.Caption:
+		t.captionDepth = depth
+	case atom.Thead:
+		t.headDepth = depth
+	case atom.Tbody, atom.Tfoot:
+		t.finishRow()
+		t.headDepth = 0
+	case atom.Tr:
+		// The end tags of rows and cells may be omitted
+		t.finishRow()
+		t.rowDepth = depth
+		t.row = &tableRow{inHead: t.headDepth != 0}
+	case atom.Td, atom.Th:
+		t.finishCell()
+		if t.row == nil {
+			t.rowDepth = depth
+			t.row = &tableRow{inHead: t.headDepth != 0}
+		}
+		t.cellDepth = depth
+		t.cell.buf.Reset()
+		t.cell.header = tok.DataAtom == atom.Th
+		t.cell.colspan = 1
+		for _, attr := range tok.Attr {
+			if attr.Key == "colspan" {
+				if n, err := strconv.Atoi(strings.TrimSpace(attr.Val)); err == nil && n > 1 && n <= 100 {
+					t.cell.colspan = n
+				}
+			}
+		}
+	case atom.A:
+		if t.linkDepth == 0 {
+			t.linkDepth = depth
+		}
+	case atom.Br, atom.P, atom.Div, atom.Li:
+		if t.cellDepth != 0 {
+			t.cell.buf.WriteByte(' ')
+		}
+	}
+}
+
+// TableText adds text to the caption or cell of the innermost table being
+// parsed.
+func (h *contentHandler) TableText(text string) {
+	ts := &h.tables
+	if len(ts.stack) == 0 {
+		return
+	}
+	t := ts.stack[len(ts.stack)-1]
+
+	switch {
+	case t.captionDepth != 0:
+		t.caption.WriteString(text)
+	case t.cellDepth != 0:
+		t.cell.buf.WriteString(text)
+	}
+
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		t.numChars++
+		if t.linkDepth != 0 {
+			t.numLinkedChars++
+		}
+	}
+}
+
+// TableEndElement completes the cells, rows and tables of the element at the
+// given depth, including any that were left open by descendants.
+func (h *contentHandler) TableEndElement(depth int) {
+	ts := &h.tables
+
+	for len(ts.stack) > 0 {
+		t := ts.stack[len(ts.stack)-1]
+
+		if t.linkDepth >= depth {
+			t.linkDepth = 0
+		}
+		if t.captionDepth >= depth {
+			t.captionDepth = 0
+		}
+		if t.cellDepth >= depth {
+			t.finishCell()
+		}
+		if t.rowDepth >= depth {
+			t.finishRow()
+		}
+		if t.headDepth >= depth {
+			t.headDepth = 0
+		}
+
+		if t.depth < depth {
+			break
+		}
+		t.finishRow()
+		ts.stack = ts.stack[:len(ts.stack)-1]
+		if table := t.table(h.offsetBlocks); table != nil {
+			ts.tables = append(ts.tables, table)
+		}
+	}
+}
+
+func (t *tableState) finishCell() {
+	if t.cellDepth == 0 || t.row == nil {
+		t.cellDepth = 0
+		return
+	}
+	t.cellDepth = 0
+
+	text := strings.Join(strings.Fields(t.cell.buf.String()), " ")
+	if n := len(strings.Fields(text)); n > t.maxCellWords {
+		t.maxCellWords = n
+	}
+
+	t.row.numCells++
+	if t.cell.header {
+		t.row.numHeaderCells++
+	}
+	t.row.cells = append(t.row.cells, text)
+	for i := 1; i < t.cell.colspan; i++ {
+		t.row.cells = append(t.row.cells, "")
+	}
+}
+
+func (t *tableState) finishRow() {
+	t.finishCell()
+	if t.row != nil && len(t.row.cells) > 0 {
+		t.rows = append(t.rows, t.row)
+	}
+	t.row = nil
+	t.rowDepth = 0
+}
+
+// table returns the data table, or nil if it's a layout table.
+func (t *tableState) table(offsetEnd int) *Table {
+	if t.layout || t.maxCellWords > maxTableCellWords {
+		return nil
+	}
+	if t.numChars == 0 || float64(t.numLinkedChars)/float64(t.numChars) > maxTableLinkDensity {
+		return nil
+	}
+
+	table := &Table{
+		Caption:     strings.Join(strings.Fields(t.caption.String()), " "),
+		offsetStart: t.offsetStart,
+		offsetEnd:   offsetEnd,
+	}
+
+	numColumns := 0
+	for _, row := range t.rows {
+		if len(row.cells) > numColumns {
+			numColumns = len(row.cells)
+		}
+	}
+
+	for i, row := range t.rows {
+		cells := row.cells
+		for len(cells) < numColumns {
+			cells = append(cells, "")
+		}
+		if i == 0 && row.isHeader() {
+			table.Header = cells
+		} else {
+			table.Rows = append(table.Rows, cells)
+		}
+	}
+
+	// Tables with a single row or column are used for layout
+	if len(table.Rows) == 0 || len(table.Header)+len(table.Rows) < 2 || numColumns < 2 {
+		return nil
+	}
+
+	return table
+}
@@ -0,0 +1,124 @@
+package boilerpipe
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const tablesTestHTML = `<html>
+<body>
+<table class="layout">
+<tr><td><a href="/">Home</a></td><td><a href="/news">News</a></td><td><a href="/sports">Sports</a></td></tr>
+<tr><td><a href="/about">About</a></td><td><a href="/contact">Contact</a></td><td><a href="/jobs">Jobs</a></td></tr>
+</table>
+<article>
+<h1>The results of the election</h1>
+<p>This is the text of the article about the results of the election, which is long enough to be the content of the document.</p>
+<table>
+<caption>Results by party</caption>
+<thead><tr><th>Party</th><th>Votes</th><th>Seats</th></tr></thead>
+<tbody>
+<tr><td>Blue | Party</td><td>1,234,567</td><td>42</td>
+<tr><td>Red Party</td><td>987,654</td><td>31</td>
+<tr><td>Others</td><td colspan="2">unknown</td>
+</tbody>
+</table>
+<p>This is more of the text of the article about the results of the election, which is also long enough to be content.</p>
+</article>
+<table><tr><td>
+<table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>
+</td></tr></table>
+</body>
+</html>`
+
+func TestTables(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(tablesTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The navigation and nested tables are layout tables
+	if l := len(doc.Tables); l != 1 {
+		t.Fatalf("expected 1 table but got %d", l)
+	}
+
+	table := doc.Tables[0]
+	if exp := "Results by party"; table.Caption != exp {
+		t.Errorf("expected caption '%s' but got '%s'", exp, table.Caption)
+	}
+	if exp := []string{"Party", "Votes", "Seats"}; !reflect.DeepEqual(table.Header, exp) {
+		t.Errorf("expected header %q but got %q", exp, table.Header)
+	}
+	exp := [][]string{
+		{"Blue | Party", "1,234,567", "42"},
+		{"Red Party", "987,654", "31"},
+		{"Others", "unknown", ""},
+	}
+	if !reflect.DeepEqual(table.Rows, exp) {
+		t.Errorf("expected rows %q but got %q", exp, table.Rows)
+	}
+
+	ArticlePipeline.Process(doc)
+	if tables := doc.ContentTables(); len(tables) != 1 || tables[0] != table {
+		t.Errorf("expected the table to be content but got %v", tables)
+	}
+}
+
+func TestTableMarkdown(t *testing.T) {
+	table := &Table{
+		Header: []string{"Party", "Votes"},
+		Rows:   [][]string{{"Blue | Party", "1,234"}, {"Red Party", "987"}},
+	}
+	exp := "| Party | Votes |\n| --- | --- |\n| Blue \\| Party | 1,234 |\n| Red Party | 987 |\n"
+	if act := table.Markdown(); act != exp {
+		t.Errorf("expected '%s' but got '%s'", exp, act)
+	}
+
+	table.Header = nil
+	exp = "|  |  |\n| --- | --- |\n| Blue \\| Party | 1,234 |\n| Red Party | 987 |\n"
+	if act := table.Markdown(); act != exp {
+		t.Errorf("expected '%s' but got '%s'", exp, act)
+	}
+}
+
+func TestTableCSV(t *testing.T) {
+	table := &Table{
+		Header: []string{"Party", "Votes"},
+		Rows:   [][]string{{"Blue Party", "1,234"}, {`The "Red" Party`, "987"}},
+	}
+	exp := "Party,Votes\nBlue Party,\"1,234\"\n\"The \"\"Red\"\" Party\",987\n"
+	if act := table.CSV(); act != exp {
+		t.Errorf("expected '%s' but got '%s'", exp, act)
+	}
+}
+
+func TestTableInFigure(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(`<html><body><article>
+<p>This is the text of the article about the results of the election, which is long enough to be the content of the document.</p>
+<figure class="wp-block-table"><table>
+<thead><tr><th>Party</th><th>Seats</th></tr></thead>
+<tbody><tr><td>Blue Party</td><td>42</td></tr><tr><td>Red Party</td><td>31</td></tr></tbody>
+</table><figcaption>Results by party</figcaption></figure>
+<p>This is more of the text of the article about the results of the election, which is also long enough to be content.</p>
+</article></body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := len(doc.Tables); l != 1 {
+		t.Fatalf("expected 1 table but got %d", l)
+	}
+	table := doc.Tables[0]
+	if exp := []string{"Party", "Seats"}; !reflect.DeepEqual(table.Header, exp) {
+		t.Errorf("expected header %q but got %q", exp, table.Header)
+	}
+	if exp := [][]string{{"Blue Party", "42"}, {"Red Party", "31"}}; !reflect.DeepEqual(table.Rows, exp) {
+		t.Errorf("expected rows %q but got %q", exp, table.Rows)
+	}
+
+	ArticlePipeline.Process(doc)
+	if tables := doc.ContentTables(); len(tables) != 1 || tables[0] != table {
+		t.Errorf("expected the table to be content but got %v", tables)
+	}
+}
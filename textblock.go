@@ -2,6 +2,7 @@ package boilerpipe
 
 import (
 	"bytes"
+	"fmt"
 	"math"
 )
 
@@ -18,8 +19,35 @@ const (
 	LabelHeading1
 	LabelHeading2
 	LabelHeading3
+	LabelByline
+	LabelComment
 )
 
+// ParseLabel returns the label with the given name, as returned by its String
+// method (e.g. "LabelTitle").
+func ParseLabel(name string) (Label, error) {
+	for label := Label(0); label < Label(len(_Label_index)-1); label++ {
+		if label.String() == name {
+			return label, nil
+		}
+	}
+	return 0, fmt.Errorf("boilerpipe: unknown label %q", name)
+}
+
+// MarshalText encodes the label as its name.
+func (label Label) MarshalText() ([]byte, error) {
+	if label < 0 || label >= Label(len(_Label_index)-1) {
+		return nil, fmt.Errorf("boilerpipe: invalid label %d", int(label))
+	}
+	return []byte(label.String()), nil
+}
+
+// UnmarshalText decodes a label from its name.
+func (label *Label) UnmarshalText(text []byte) (err error) {
+	*label, err = ParseLabel(string(text))
+	return
+}
+
 type LabelStack struct {
 	labels []Label
 }
@@ -64,19 +92,19 @@ func (x *LabelStack) Push(labels ...Label) {
 }
 
 type TextBlock struct {
-	Text string
+	Text string `json:"text"`
 
-	OffsetBlocksStart int
-	OffsetBlocksEnd   int
+	OffsetBlocksStart int `json:"offsetBlocksStart"`
+	OffsetBlocksEnd   int `json:"offsetBlocksEnd"`
 
-	NumWords               int
-	NumLinkedWords         int
-	NumWordsInWrappedLines int
-	NumWrappedLines        int
+	NumWords               int `json:"numWords"`
+	NumLinkedWords         int `json:"numLinkedWords"`
+	NumWordsInWrappedLines int `json:"numWordsInWrappedLines"`
+	NumWrappedLines        int `json:"numWrappedLines"`
 
-	TagLevel int
+	TagLevel int `json:"tagLevel"`
 
-	IsContent bool
+	IsContent bool `json:"isContent"`
 
 	labelMap map[Label]int
 }
@@ -0,0 +1,144 @@
+package boilerpipe
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// reTitleSeparator matches the separators between the headline of a document
+// title and its site name, such as " | " or " - " or "_".
+var reTitleSeparator = regexp.MustCompile(`\s+(?:[|\-–—»·:]+|::)\s+|\s*[|_｜]\s*|\s*--\s*`)
+
+// cleanTitle returns the document title without its site name, by preferring
+// the title candidate which matches a heading block, and otherwise removing
+// the parts of the title which match the site name or URL host.
+func (doc *Document) cleanTitle() string {
+	title := strings.Join(strings.Fields(doc.Title), " ")
+	if title == "" {
+		return ""
+	}
+
+	// A candidate matching a heading is very likely the headline, with
+	// level 1 headings checked first.
+	candidates := titleCandidates(title)
+	for _, label := range []Label{LabelHeading1, LabelHeading} {
+		for _, tb := range doc.TextBlocks {
+			if !tb.HasLabel(label) {
+				continue
+			}
+			for _, pot := range candidates {
+				if matchesTitle(map[string]bool{normalizeTitle(pot): true}, tb.Text) {
+					return pot
+				}
+			}
+		}
+	}
+
+	return stripSiteName(title, doc.siteNames())
+}
+
+// siteNames returns the lowercase names the document's site may be referred
+// to by in its title.
+func (doc *Document) siteNames() (names []string) {
+	addName := func(name string) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return
+		}
+		names = appendUnique(names, name)
+
+		// Also use the domain name of site names like "Example.com"
+		if i := strings.LastIndexByte(name, '.'); i > 0 && !strings.Contains(name, " ") {
+			names = appendUnique(names, name[:i])
+		}
+	}
+
+	addName(doc.SiteName)
+	if doc.Publisher != nil {
+		addName(doc.Publisher.Name)
+	}
+
+	u := doc.CanonicalURL
+	if u == nil {
+		u = doc.URL
+	}
+	if u != nil {
+		// Use the domain name without the public suffix, e.g. "example" for
+		// "www.example.co.uk".
+		labels := strings.Split(strings.ToLower(u.Hostname()), ".")
+		for i := len(labels) - 2; i >= 0; i-- {
+			if len(labels[i]) > 3 || i == 0 {
+				names = appendUnique(names, labels[i])
+				break
+			}
+		}
+	}
+	return
+}
+
+// stripSiteName removes the leading or trailing parts of a title which match
+// one of the site names, leaving at least one part.
+func stripSiteName(title string, siteNames []string) string {
+	if len(siteNames) == 0 {
+		return title
+	}
+
+	locs := reTitleSeparator.FindAllStringIndex(title, -1)
+	if len(locs) == 0 {
+		return title
+	}
+
+	// Part i spans from the end of separator i-1 to the start of separator i
+	start, end := 0, len(locs)
+	partAt := func(i int) string {
+		from, to := 0, len(title)
+		if i > 0 {
+			from = locs[i-1][1]
+		}
+		if i < len(locs) {
+			to = locs[i][0]
+		}
+		return title[from:to]
+	}
+
+	for end > start && isSiteName(partAt(end), siteNames) {
+		end--
+	}
+	for start < end && isSiteName(partAt(start), siteNames) {
+		start++
+	}
+
+	from, to := 0, len(title)
+	if start > 0 {
+		from = locs[start-1][1]
+	}
+	if end < len(locs) {
+		to = locs[end][0]
+	}
+	return strings.TrimSpace(title[from:to])
+}
+
+// isSiteName returns true if the title part is, or contains, one of the site
+// names ignoring case, spaces and punctuation.
+func isSiteName(part string, siteNames []string) bool {
+	compact := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, s)
+	}
+
+	p := compact(part)
+	if p == "" {
+		return false
+	}
+	for _, name := range siteNames {
+		if n := compact(name); n != "" && (p == n || strings.Contains(p, n) && len(strings.Fields(part)) <= 4) {
+			return true
+		}
+	}
+	return false
+}
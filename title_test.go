@@ -0,0 +1,51 @@
+package boilerpipe
+
+import (
+	"strings"
+	"testing"
+)
+
+var stripSiteNameTestData = []struct {
+	title     string
+	siteNames []string
+	exp       string
+}{
+	{"Headline of the story | Example News", []string{"example news"}, "Headline of the story"},
+	{"Example News - Headline of the story", []string{"example news"}, "Headline of the story"},
+	{"Headline of the story - Example News Online | Example", []string{"example"}, "Headline of the story"},
+	{"Lease: No rent for Raiders - Las Vegas Sun Newspaper", []string{"lasvegassun"}, "Lease: No rent for Raiders"},
+	{"共绘美美与共的人类文明画卷|人类|世界_新浪新闻", []string{"sina"}, "共绘美美与共的人类文明画卷|人类|世界_新浪新闻"},
+	{"Headline - with a dash", []string{"example"}, "Headline - with a dash"},
+	{"Example", []string{"example"}, "Example"},
+	{"Headline | Example News", nil, "Headline | Example News"},
+}
+
+func TestStripSiteName(t *testing.T) {
+	for _, test := range stripSiteNameTestData {
+		if act := stripSiteName(test.title, test.siteNames); act != test.exp {
+			t.Errorf("%s: expected '%s' but got '%s'", test.title, test.exp, act)
+		}
+	}
+}
+
+var cleanTitleTestData = map[string]string{
+	// Matches the H1 block
+	`<html><head><title>Headline: A Story - Some Site</title></head><body><h1>Headline: A Story</h1></body></html>`: "Headline: A Story",
+	// Matches the og:site_name
+	`<html><head><title>Headline: A Story | Some Site</title><meta property="og:site_name" content="Some Site"></head><body><p>Text</p></body></html>`: "Headline: A Story",
+	// Nothing to strip
+	`<html><head><title>Headline: A Story | Some Site</title></head><body><p>Text</p></body></html>`: "Headline: A Story | Some Site",
+	`<html><body><p>Text</p></body></html>`:                                                          "",
+}
+
+func TestCleanTitle(t *testing.T) {
+	for s, exp := range cleanTitleTestData {
+		doc, err := ParseDocument(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.CleanTitle != exp {
+			t.Errorf("%s: expected '%s' but got '%s'", s, exp, doc.CleanTitle)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package boilerpipe
+
+import (
+	"context"
+	"sort"
+)
+
+// A TracingPipeline is a Pipeline which records a snapshot of the document
+// after each of its filters, along with the text blocks each filter changed.
+type TracingPipeline struct {
+	Pipeline *Pipeline
+
+	// Start is a snapshot of the document before it was processed.
+	Start *Document
+
+	// Steps are the filters which processed the document, in order.
+	Steps []TraceStep
+}
+
+// A TraceStep is a filter which processed a document in a TracingPipeline.
+type TraceStep struct {
+	FilterName string
+	HasChanged bool
+
+	// Document is a snapshot of the document after the filter processed it.
+	Document *Document
+
+	// Diffs are the text blocks the filter changed.
+	Diffs []TextBlockDiff
+}
+
+// Statically check that *TracingPipeline satisfies the Filter and
+// ContextFilter interfaces.
+var (
+	_ Filter        = (*TracingPipeline)(nil)
+	_ ContextFilter = (*TracingPipeline)(nil)
+)
+
+// Name returns the pipeline name.
+func (pipeline *TracingPipeline) Name() string { return pipeline.Pipeline.Name() }
+
+// Process runs a document through the filters of the pipeline, replacing the
+// trace of any previous document.
+func (pipeline *TracingPipeline) Process(doc *Document) (hasChanged bool) {
+	hasChanged, _ = pipeline.ProcessContext(context.Background(), doc)
+	return
+}
+
+// ProcessContext is like Process, but stops before the next filter once the
+// context is done and returns the context's error.
+func (pipeline *TracingPipeline) ProcessContext(ctx context.Context, doc *Document) (hasChanged bool, err error) {
+	pipeline.Start = doc.Clone()
+	pipeline.Steps = nil
+
+	// Run a copy of the pipeline with a hook which snapshots the document
+	// after each filter
+	before := pipeline.Start
+	p := *pipeline.Pipeline
+	p.Hooks = append(p.Hooks[:len(p.Hooks):len(p.Hooks)], Hook{
+		AfterFilter: func(name string, doc *Document, hasChanged bool) {
+			after := doc.Clone()
+			pipeline.Steps = append(pipeline.Steps, TraceStep{
+				FilterName: name,
+				HasChanged: hasChanged,
+				Document:   after,
+				Diffs:      DiffTextBlocks(before.TextBlocks, after.TextBlocks),
+			})
+			before = after
+		},
+	})
+
+	return p.ProcessContext(ctx, doc)
+}
+
+// A TextBlockDiff is a text block which differs between two snapshots of a
+// document. Text blocks are matched by their OffsetBlocksStart, so a block
+// merged into the one before it is removed.
+type TextBlockDiff struct {
+	// Before is the text block before it was changed, or nil if it was
+	// added.
+	Before *TextBlock
+
+	// After is the text block after it was changed, or nil if it was
+	// removed.
+	After *TextBlock
+}
+
+// DiffTextBlocks returns the text blocks which differ between before and
+// after, ordered by their offset.
+func DiffTextBlocks(before, after []*TextBlock) (diffs []TextBlockDiff) {
+	afterMap := make(map[int]*TextBlock, len(after))
+	for _, tb := range after {
+		afterMap[tb.OffsetBlocksStart] = tb
+	}
+
+	beforeMap := make(map[int]*TextBlock, len(before))
+	for _, tb := range before {
+		beforeMap[tb.OffsetBlocksStart] = tb
+		if a := afterMap[tb.OffsetBlocksStart]; a == nil || !tb.equal(a) {
+			diffs = append(diffs, TextBlockDiff{Before: tb, After: a})
+		}
+	}
+	for _, tb := range after {
+		if beforeMap[tb.OffsetBlocksStart] == nil {
+			diffs = append(diffs, TextBlockDiff{After: tb})
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].offset() < diffs[j].offset()
+	})
+	return
+}
+
+func (d TextBlockDiff) offset() int {
+	if d.Before != nil {
+		return d.Before.OffsetBlocksStart
+	}
+	return d.After.OffsetBlocksStart
+}
+
+// ContentChanged returns true if the block was added or removed, or if it
+// changed whether it is content.
+func (d TextBlockDiff) ContentChanged() bool {
+	return d.Before == nil || d.After == nil || d.Before.IsContent != d.After.IsContent
+}
+
+// LabelsAdded returns the labels the block was given.
+func (d TextBlockDiff) LabelsAdded() []Label {
+	return labelsDifference(d.After, d.Before)
+}
+
+// LabelsRemoved returns the labels the block lost.
+func (d TextBlockDiff) LabelsRemoved() []Label {
+	return labelsDifference(d.Before, d.After)
+}
+
+// labelsDifference returns the labels of a which b doesn't have, in order.
+func labelsDifference(a, b *TextBlock) (labels []Label) {
+	if a == nil {
+		return
+	}
+	for label := range a.labelMap {
+		if b == nil || !b.HasLabel(label) {
+			labels = append(labels, label)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i] < labels[j] })
+	return
+}
+
+// equal returns true if the text blocks are the same, including their
+// labels.
+func (tb *TextBlock) equal(other *TextBlock) bool {
+	if tb.Text != other.Text ||
+		tb.OffsetBlocksStart != other.OffsetBlocksStart ||
+		tb.OffsetBlocksEnd != other.OffsetBlocksEnd ||
+		tb.NumWords != other.NumWords ||
+		tb.NumLinkedWords != other.NumLinkedWords ||
+		tb.NumWordsInWrappedLines != other.NumWordsInWrappedLines ||
+		tb.NumWrappedLines != other.NumWrappedLines ||
+		tb.TagLevel != other.TagLevel ||
+		tb.IsContent != other.IsContent ||
+		len(tb.labelMap) != len(other.labelMap) {
+		return false
+	}
+	for label, count := range tb.labelMap {
+		if other.labelMap[label] != count {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package boilerpipe
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTracingPipeline(t *testing.T) {
+	f, err := os.Open("testdata/1.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	doc, err := ParseDocument(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline := &TracingPipeline{Pipeline: ArticlePipeline}
+	pipeline.Process(doc)
+
+	if l := len(pipeline.Steps); l != len(ArticlePipeline.Filters) {
+		t.Fatalf("expected %d steps but got %d", len(ArticlePipeline.Filters), l)
+	}
+
+	before := pipeline.Start
+	for i, step := range pipeline.Steps {
+		if exp := ArticlePipeline.Filters[i].Name(); step.FilterName != exp {
+			t.Errorf("expected filter name '%s' but got '%s'", exp, step.FilterName)
+		}
+		if !step.HasChanged && len(step.Diffs) > 0 {
+			t.Errorf("%s: expected no diffs for unchanged step but got %d", step.FilterName, len(step.Diffs))
+		}
+		if exp := DiffTextBlocks(before.TextBlocks, step.Document.TextBlocks); !reflect.DeepEqual(step.Diffs, exp) {
+			t.Errorf("%s: expected diffs to match the snapshots", step.FilterName)
+		}
+		before = step.Document
+	}
+
+	// The snapshots must not be changed by the filters which follow
+	if step := pipeline.Steps[0]; countContent(step.Document) == countContent(doc) {
+		t.Errorf("expected first snapshot to differ from the processed document")
+	}
+	if act, exp := pipeline.Steps[len(pipeline.Steps)-1].Document.Content(), doc.Content(); act != exp {
+		t.Errorf("expected last snapshot content '%s' but got '%s'", exp, act)
+	}
+}
+
+func countContent(doc *Document) (n int) {
+	for _, tb := range doc.TextBlocks {
+		if tb.IsContent {
+			n++
+		}
+	}
+	return
+}
+
+func TestDiffTextBlocks(t *testing.T) {
+	newBlock := func(offset int, isContent bool, labels ...Label) *TextBlock {
+		tb := NewTextBlock()
+		tb.OffsetBlocksStart = offset
+		tb.OffsetBlocksEnd = offset
+		tb.IsContent = isContent
+		return tb.AddLabels(labels...)
+	}
+
+	before := []*TextBlock{
+		newBlock(0, false, LabelTitle),
+		newBlock(1, false),
+		newBlock(2, true),
+		newBlock(3, true),
+	}
+	after := []*TextBlock{
+		newBlock(0, false, LabelTitle),
+		newBlock(1, true, LabelMightBeContent),
+		newBlock(2, true),
+	}
+	after[2].OffsetBlocksEnd = 3
+
+	diffs := DiffTextBlocks(before, after)
+	if len(diffs) != 3 {
+		t.Fatalf("expected 3 diffs but got %d", len(diffs))
+	}
+
+	if d := diffs[0]; d.Before != before[1] || d.After != after[1] || !d.ContentChanged() {
+		t.Errorf("expected block 1 to change content")
+	}
+	if exp, act := []Label{LabelMightBeContent}, diffs[0].LabelsAdded(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("expected labels added %v but got %v", exp, act)
+	}
+	if d := diffs[1]; d.Before != before[2] || d.After != after[2] || d.ContentChanged() {
+		t.Errorf("expected block 2 to be merged")
+	}
+	if d := diffs[2]; d.Before != before[3] || d.After != nil || !d.ContentChanged() {
+		t.Errorf("expected block 3 to be removed")
+	}
+}